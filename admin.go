@@ -0,0 +1,126 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PurposeAdmin is the purpose of the tokens of the admin commands that
+// await the confirmation of the owner.
+const PurposeAdmin = "admin"
+
+// ErrNoAdminCommands is returned when there are no pending admin commands
+// with a given ID, because they have already been confirmed or cancelled,
+// or because they have expired.
+var ErrNoAdminCommands = errors.New("no pending admin commands")
+
+// adminCommandsPath returns the path of the pending admin commands with the
+// given ID, or [ErrNoAdminCommands] if the ID is not a valid one.
+func (nl *Newsletter) adminCommandsPath(id string) (string, error) {
+	valid := id != "" && !strings.ContainsFunc(id, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= '2' && r <= '7' || r == '=')
+	})
+	if !valid {
+		return "", fmt.Errorf("%w with ID %q", ErrNoAdminCommands, id)
+	}
+	return filepath.Join(nl.Config.Dir, AdminDir, id), nil
+}
+
+// SaveAdminCommands stores the given command lines until they are
+// confirmed by the owner, and returns their ID. They are private, as they
+// contain subscriber addresses. The commands that have
+// expired are removed at the same time.
+func (nl *Newsletter) SaveAdminCommands(lines []string) (string, error) {
+	dir := filepath.Join(nl.Config.Dir, AdminDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("create admin dir: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("read admin dir: %w", err)
+	}
+	expiry := nl.Config.Settings.ConfirmExpiryOrDefault()
+	for _, e := range entries {
+		info, err := e.Info()
+		if err == nil && nl.now().Sub(info.ModTime()) > expiry {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+
+	content := strings.Join(lines, "\n") + "\n"
+	id := nl.HashWithSecret(content)
+	path, err := nl.adminCommandsPath(id)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(path, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("write admin commands: %w", err)
+	}
+	return id, nil
+}
+
+// TakeAdminCommands removes the pending admin commands with the given ID
+// and returns their lines, or [ErrNoAdminCommands] if there are none. They
+// are removed before being returned so that they are only run once.
+func (nl *Newsletter) TakeAdminCommands(id string) ([]string, error) {
+	path, err := nl.adminCommandsPath(id)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := readLines(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w with ID %s", ErrNoAdminCommands, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read admin commands: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("remove admin commands: %w", err)
+	}
+	return lines, nil
+}
+
+// AdminID returns the Message-ID of the report asking the owner to confirm
+// the pending admin commands with the given ID.
+func (nl *Newsletter) AdminID(id string) string {
+	return nl.GenerateId(nl.NewToken(PurposeAdmin, nl.LocalUserAddr(), id))
+}
+
+// VerifyAdminID checks that messageID has been generated by
+// [Newsletter.AdminID], and returns the ID of the pending admin commands.
+func (nl *Newsletter) VerifyAdminID(messageID string) (string, error) {
+	token, err := nl.GetHashFromId(messageID)
+	if err != nil {
+		return "", err
+	}
+	data, err := nl.VerifyToken(token, PurposeAdmin, nl.LocalUserAddr())
+	if err != nil {
+		return "", err
+	}
+	if len(data) != 1 {
+		return "", ErrInvalidToken
+	}
+	return data[0], nil
+}
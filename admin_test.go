@@ -0,0 +1,72 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestAdminCommands(t *testing.T) {
+	nl := fakeNewsletter(t)
+	lines := []string{"add test@club1.fr", "remove recipient@club1.fr"}
+	id, err := nl.SaveAdminCommands(lines)
+	if err != nil {
+		t.Fatalf("save admin commands: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(nl.Config.Dir, newsletter.AdminDir, id))
+	if err != nil {
+		t.Fatalf("stat admin commands: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected admin commands to be private, got mode %v", perm)
+	}
+
+	verified, err := nl.VerifyAdminID(nl.AdminID(id))
+	if err != nil {
+		t.Fatalf("verify admin ID: %v", err)
+	}
+	if verified != id {
+		t.Errorf("expected ID %q, got %q", id, verified)
+	}
+	if _, err := nl.VerifyAdminID(nl.ApprovalID(nl.LocalUserAddr())); !errors.Is(err, newsletter.ErrInvalidToken) {
+		t.Errorf("expected error %v for an approval ID, got: %v", newsletter.ErrInvalidToken, err)
+	}
+
+	taken, err := nl.TakeAdminCommands(id)
+	if err != nil {
+		t.Fatalf("take admin commands: %v", err)
+	}
+	if !reflect.DeepEqual(taken, lines) {
+		t.Errorf("expected lines %q, got %q", lines, taken)
+	}
+	if _, err := nl.TakeAdminCommands(id); !errors.Is(err, newsletter.ErrNoAdminCommands) {
+		t.Errorf("expected error %v when taken twice, got: %v", newsletter.ErrNoAdminCommands, err)
+	}
+	if _, err := nl.TakeAdminCommands("../settings.json"); !errors.Is(err, newsletter.ErrNoAdminCommands) {
+		t.Errorf("expected error %v for an invalid ID, got: %v", newsletter.ErrNoAdminCommands, err)
+	}
+}
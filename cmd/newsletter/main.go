@@ -20,6 +20,7 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
@@ -27,9 +28,15 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
 
 	"charm.land/huh/v2"
 	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/exchange"
 	"github.com/club-1/newsletter-go/v3/mailer"
 	"github.com/club-1/newsletter-go/v3/messages"
 )
@@ -43,6 +50,17 @@ var (
 	flagVerbose bool
 	flagYes     bool
 	flagPreview bool
+	flagConfirm bool
+	flagForce   bool
+	flagFormat  string
+	flagList    string
+	flagConfig  string
+	flagSegment string
+	flagAt      string
+	flagSince   string
+	flagUntil   string
+	flagAddress string
+	flagEvent   string
 	flagHelp    bool
 	flagVersion bool
 )
@@ -102,34 +120,88 @@ func printPreview(mail *mailer.Mail) {
 	fmt.Print("================  PREVIEW END  ================\n")
 }
 
-func initForwardFiles() error {
+// forwardFileName returns the name of the forward file of the given route
+// of the list, with the recipient delimiter of its addresses.
+func forwardFileName(nl *newsletter.Newsletter, route string) string {
+	return ".forward" + nl.Delimiter() + nl.RouteExtension(route)
+}
+
+// shellQuote quotes s for the shell if needed.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/._-+") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// forwardCommand returns the command that the forward file of the given
+// route pipes the mails into. The config directory is given to the command
+// if it is not empty.
+func forwardCommand(prefix string, configDir string, list string, route string) string {
+	cmd := filepath.Join(prefix, "sbin/newsletterctl")
+	if configDir != "" {
+		cmd += " -C " + shellQuote(configDir)
+	}
+	if list != "" {
+		cmd += " -list " + list
+	}
+	return cmd + " " + route
+}
+
+// initForwardFiles writes the forward files of the given routes, and
+// rewrites the existing ones that run another command. The config directory
+// of the default list is the given one, or [newsletter.BaseConfigDir] if it
+// is empty. It is written in the files if it is not the default one, as the
+// environment of the MTA may differ.
+func initForwardFiles(nl *newsletter.Newsletter, configDir string, routes []string) error {
 	prefix, err := getCmdPrefix()
 	if err != nil {
 		return fmt.Errorf("get command prefix: %w", err)
 	}
 
+	configDir, err = newsletter.ConfigDir(configDir, "")
+	if err != nil {
+		return err
+	}
+	defaultDir, err := newsletter.DefaultConfigDir()
+	if err != nil {
+		return err
+	}
+	if configDir == defaultDir {
+		configDir = ""
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("get user home directory: %w", err)
 	}
 
 	errCount := 0
-	for _, route := range newsletter.Routes {
-		fileName := ".forward+" + route
+	for _, route := range routes {
+		fileName := forwardFileName(nl, route)
 		filePath := filepath.Join(homeDir, fileName)
-		_, err = os.Stat(filePath)
-		if errors.Is(err, os.ErrNotExist) {
+		content := []byte("| \"" + forwardCommand(prefix, configDir, nl.List, route) + "\"\n")
+		previous, err := os.ReadFile(filePath)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
 			if flagVerbose {
 				fmt.Printf("writting file %q\n", filePath)
 			}
-
-			cmdPath := filepath.Join(prefix, "sbin/newsletterctl")
-			content := []byte("| \"" + cmdPath + " " + route + "\"\n")
-			err := os.WriteFile(filePath, content, 0664)
-			if err != nil {
-				log.Printf("cannot write file %q: %v", filePath, err)
-				errCount++
-			}
+		case err != nil:
+			log.Printf("cannot read file %q: %v", filePath, err)
+			errCount++
+			continue
+		case bytes.Equal(previous, content):
+			continue
+		default:
+			// Tell it even when not verbose, as the mails were processed
+			// by another command, like with another config directory.
+			fmt.Printf("rewriting file %q, it contained: %s", filePath, previous)
+		}
+		err = os.WriteFile(filePath, content, 0664)
+		if err != nil {
+			log.Printf("cannot write file %q: %v", filePath, err)
+			errCount++
 		}
 	}
 	if errCount > 0 {
@@ -145,8 +217,8 @@ func stop(nl *newsletter.Newsletter) error {
 	}
 
 	errCount := 0
-	for _, route := range newsletter.Routes {
-		fileName := ".forward+" + route
+	for _, route := range nl.ForwardRoutes() {
+		fileName := forwardFileName(nl, route)
 		filePath := filepath.Join(homeDir, fileName)
 		if flagVerbose {
 			fmt.Printf("deleting file %q\n", filePath)
@@ -164,11 +236,79 @@ func stop(nl *newsletter.Newsletter) error {
 	return nil
 }
 
-func setup(nl *newsletter.Newsletter) error {
-	err := initForwardFiles()
+// parseTopics parses a comma or space separated list of topics.
+func parseTopics(s string) ([]string, error) {
+	topics := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for _, topic := range topics {
+		if err := newsletter.ValidateTopic(topic); err != nil {
+			return nil, err
+		}
+	}
+	return topics, nil
+}
+
+// Actions on the secret send address in the setup.
+const (
+	sendTokenKeep   = "keep"
+	sendTokenRotate = "rotate"
+	sendTokenRemove = "remove"
+)
+
+// isListable reports whether the given directory can be listed by other
+// users than its owner, who can then see the names of the files in it.
+func isListable(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.Mode().Perm()&0044 != 0
+}
+
+// updateSendToken applies the given action to the secret send address,
+// removing the forward file of the previous one if it changes.
+func updateSendToken(nl *newsletter.Newsletter, action string) error {
+	oldRoute := nl.SendTokenRoute()
+	var err error
+	switch action {
+	case sendTokenRotate:
+		err = nl.Config.RotateSendToken()
+	case sendTokenRemove:
+		err = nl.Config.RemoveSendToken()
+		nl.Config.Settings.RequireSendToken = false
+	default:
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	if oldRoute == "" {
+		return nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("get user home directory: %w", err)
+	}
+	err = os.Remove(filepath.Join(homeDir, forwardFileName(nl, oldRoute)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove forward file of the previous send address: %w", err)
+	}
+	return nil
+}
+
+func setup(nl *newsletter.Newsletter) error {
+	topics := strings.Join(nl.Config.Settings.Topics, ", ")
+	language := nl.Config.LanguageOrDefault()
+
+	sendTokenAction := sendTokenKeep
+	sendTokenOptions := []huh.Option[string]{huh.NewOption("generate a new one", sendTokenRotate)}
+	if nl.Config.SendToken == "" {
+		sendTokenAction = sendTokenRemove
+		sendTokenOptions = append(sendTokenOptions, huh.NewOption("none", sendTokenRemove))
+	} else {
+		sendTokenOptions = append(sendTokenOptions,
+			huh.NewOption("keep "+nl.SendTokenAddr(), sendTokenKeep),
+			huh.NewOption("remove it", sendTokenRemove),
+		)
+	}
 
 	setupForm := huh.NewForm(
 		huh.NewGroup(
@@ -189,7 +329,25 @@ func setup(nl *newsletter.Newsletter) error {
 					huh.NewOption("english", messages.LangEnglish),
 					huh.NewOption("french", messages.LangFrench),
 				).
-				Value(&nl.Config.Settings.Language),
+				Value(&language),
+			huh.NewInput().
+				Title("Topics").
+				Description("Comma separated topics that subscribers can choose from (optional)").
+				Validate(func(s string) error {
+					_, err := parseTopics(s)
+					return err
+				}).
+				Value(&topics),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Secret send address").
+				Description("Address to send news by email that only you know, as the From address can be forged").
+				Options(sendTokenOptions...).
+				Value(&sendTokenAction),
+			huh.NewConfirm().
+				Title("Only accept news sent to the secret send address ?").
+				Value(&nl.Config.Settings.RequireSendToken),
 		),
 		huh.NewGroup(
 			huh.NewText().
@@ -201,6 +359,27 @@ func setup(nl *newsletter.Newsletter) error {
 	if err := setupForm.Run(); err != nil {
 		return fmt.Errorf("build setup form: %w", err)
 	}
+	nl.Config.Settings.Topics, _ = parseTopics(topics)
+	// Keep following the system default unless another language is chosen.
+	if language != nl.Config.LanguageOrDefault() {
+		nl.Config.Settings.Language = language
+	}
+
+	if err := updateSendToken(nl, sendTokenAction); err != nil {
+		return err
+	}
+	if addr := nl.SendTokenAddr(); addr != "" {
+		fmt.Printf("🔑 secret send address: %s\n", addr)
+		if homeDir, err := os.UserHomeDir(); err == nil && isListable(homeDir) {
+			fmt.Printf("⚠️  the token is in the name of a forward file, and %q can be listed by other users\n", homeDir)
+		}
+	}
+
+	err := initForwardFiles(nl, flagConfig, nl.ForwardRoutes())
+	if err != nil {
+		return err
+	}
+
 	err = nl.Config.SaveSettings()
 	if err != nil {
 		return err
@@ -215,6 +394,7 @@ func setup(nl *newsletter.Newsletter) error {
 	if flagVerbose {
 		fmt.Printf("signature sucessfully saved to file %q\n", newsletter.SignatureFile)
 	}
+	record(nl, newsletter.JournalEntry{Event: newsletter.EventConfig, Outcome: newsletter.OutcomeOK, Detail: "setup"})
 	fmt.Println("💾 saved !")
 	return nil
 }
@@ -225,10 +405,26 @@ func send(nl *newsletter.Newsletter, args []string) error {
 		return err
 	}
 
+	if flagSegment != "" && !slices.Contains(nl.Config.Settings.Topics, flagSegment) {
+		return fmt.Errorf("unknown topic: %q", flagSegment)
+	}
+
 	mail := nl.DefaultMail(subject, body)
-	mail.Body += fmt.Sprintf(messages.Newsletter_footer.Print(), nl.UnsubscribeAddr())
+	mail.Body += nl.Footer(flagSegment)
+
+	addrCount, err := nl.CountSegment(flagSegment)
+	if err != nil {
+		return fmt.Errorf("count subscribers: %w", err)
+	}
 
-	addrCount := len(nl.Config.Emails)
+	var sendAt time.Time
+	when := ""
+	if flagAt != "" {
+		if sendAt, err = nl.ParseSendAt(flagAt); err != nil {
+			return err
+		}
+		when = " on " + nl.FormatSendAt(sendAt)
+	}
 
 	if !flagYes {
 		err = nl.SendPreviewMail(*mail)
@@ -240,14 +436,14 @@ func send(nl *newsletter.Newsletter, args []string) error {
 			os.Exit(0)
 		}
 
-		duration := float32(addrCount) / 5.0
+		duration := (time.Duration(addrCount) * nl.SendInterval()).Round(time.Second)
 
 		var confirm bool
 		confirmForm := huh.NewForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title(fmt.Sprintf("Do you really want to send this to %v email addresses ?\n", addrCount)).
-					Description(fmt.Sprintf("this will take %v seconds", duration)).
+					Title(fmt.Sprintf("Do you really want to send this to %v email addresses%s ?\n", addrCount, when)).
+					Description(fmt.Sprintf("this will take %v", duration)).
 					Value(&confirm),
 			),
 		)
@@ -260,9 +456,13 @@ func send(nl *newsletter.Newsletter, args []string) error {
 		}
 	}
 
+	if !sendAt.IsZero() {
+		return scheduleIssue(nl, subject, body, mail, sendAt, addrCount)
+	}
+
 	fmt.Print("sending ")
 	var errCount = 0
-	for err := range nl.SendNews(mail) {
+	for err := range nl.SendSegment(mail, flagSegment) {
 		if err != nil {
 			errCount++
 			fmt.Print("x")
@@ -272,6 +472,19 @@ func send(nl *newsletter.Newsletter, args []string) error {
 	}
 	fmt.Printf(" done !\n")
 
+	entry := newsletter.JournalEntry{
+		Event:   newsletter.RouteSend,
+		Outcome: newsletter.OutcomeOK,
+		Detail:  fmt.Sprintf("sent to %v subscribers with %v error(s)", addrCount, errCount),
+	}
+	if flagSegment != "" {
+		entry.Detail += ", segment: " + flagSegment
+	}
+	if errCount > 0 {
+		entry.Outcome = newsletter.OutcomeError
+	}
+	record(nl, entry)
+
 	if errCount > 0 {
 		return fmt.Errorf("error occured while sending mail to %v addresses", errCount)
 	}
@@ -280,6 +493,638 @@ func send(nl *newsletter.Newsletter, args []string) error {
 	return nil
 }
 
+// scheduleIssue queues the given mail, to be sent at the given time by the
+// run-queue command.
+func scheduleIssue(nl *newsletter.Newsletter, subject string, body string, mail *mailer.Mail, sendAt time.Time, addrCount int) error {
+	issue := &newsletter.Issue{
+		ID:        nl.HashWithSecret(subject + "\x00" + body + "\x00" + flagSegment + "\x00" + sendAt.String()),
+		Subject:   subject,
+		Submitter: nl.LocalUserAddr(),
+		Segment:   flagSegment,
+		SendAt:    sendAt,
+	}
+	err := nl.CreateIssue(issue, body, nil)
+	if err == nil {
+		err = nl.QueueIssue(issue, mail)
+	}
+	at := nl.FormatSendAt(sendAt)
+	entry := newsletter.JournalEntry{
+		Event:   newsletter.RouteSend,
+		Outcome: newsletter.OutcomeOK,
+		Detail:  fmt.Sprintf("scheduled for %v subscribers on %s, issue: %s", addrCount, at, issue.ID),
+	}
+	if flagSegment != "" {
+		entry.Detail += ", segment: " + flagSegment
+	}
+	if err != nil {
+		entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+	}
+	record(nl, entry)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ newsletter scheduled on %s, issue: %s\n", at, issue.ID)
+	return nil
+}
+
+// openArg opens the file given as first argument, or returns the standard
+// input if it is missing.
+func openArg(args []string) (io.ReadCloser, string, error) {
+	switch len(args) {
+	case 0:
+		stat, _ := os.Stdin.Stat()
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return nil, "", fmt.Errorf("missing STDIN piped input")
+		}
+		return os.Stdin, "", nil
+	case 1:
+		file, err := os.Open(args[0])
+		if err != nil {
+			return nil, "", err
+		}
+		return file, args[0], nil
+	default:
+		return nil, "", fmt.Errorf("too many arguments")
+	}
+}
+
+// getFormat returns the format selected with -f, or the one guessed from
+// the given file path.
+func getFormat(path string) (exchange.Format, error) {
+	if flagFormat == "" {
+		return exchange.DetectFormat(path), nil
+	}
+	format := exchange.Format(flagFormat)
+	if !slices.Contains(exchange.Formats[:], format) {
+		return "", fmt.Errorf("unknown format: %q", flagFormat)
+	}
+	return format, nil
+}
+
+func addSubscribers(nl *newsletter.Newsletter, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing address")
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	errCount := 0
+	for _, addr := range args {
+		err := nl.Config.CheckAllowed(addr, false)
+		if flagForce && errors.Is(err, newsletter.ErrSuppressed) {
+			err = nl.Config.Unsuppress(addr)
+		}
+		if err == nil {
+			err = nl.Config.Subscribers.Add(&newsletter.Subscriber{Address: addr, SubscribedAt: now})
+		}
+		entry := newsletter.JournalEntry{Event: newsletter.EventAdd, Address: addr, Outcome: newsletter.OutcomeOK}
+		if err != nil {
+			entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+		}
+		record(nl, entry)
+		if err != nil {
+			log.Printf("cannot add address %s: %v", addr, err)
+			errCount++
+			continue
+		}
+		if flagVerbose {
+			fmt.Printf("address added: %s\n", addr)
+		}
+	}
+	fmt.Printf("➕ %v address(es) added\n", len(args)-errCount)
+	if errCount > 0 {
+		return fmt.Errorf("%v address(es) could not be added", errCount)
+	}
+	return nil
+}
+
+func removeSubscribers(nl *newsletter.Newsletter, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing address")
+	}
+	errCount := 0
+	for _, addr := range args {
+		err := nl.Config.Remove(addr)
+		entry := newsletter.JournalEntry{Event: newsletter.EventRemove, Address: addr, Outcome: newsletter.OutcomeOK}
+		if err != nil {
+			entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+		}
+		record(nl, entry)
+		if err != nil {
+			log.Printf("cannot remove address %s: %v", addr, err)
+			errCount++
+			continue
+		}
+		if flagVerbose {
+			fmt.Printf("address removed: %s\n", addr)
+		}
+	}
+	fmt.Printf("➖ %v address(es) removed\n", len(args)-errCount)
+	if errCount > 0 {
+		return fmt.Errorf("%v address(es) could not be removed", errCount)
+	}
+	return nil
+}
+
+func importSubscribers(nl *newsletter.Newsletter, args []string) error {
+	file, path, err := openArg(args)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	format, err := getFormat(path)
+	if err != nil {
+		return err
+	}
+	subs, err := exchange.Read(file, format)
+	if err != nil {
+		return fmt.Errorf("read %s subscribers: %w", format, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	added, skipped, errCount := 0, 0, 0
+	for _, sub := range subs {
+		subscribed, err := nl.Config.IsSubscribed(sub.Address)
+		if err != nil {
+			return fmt.Errorf("check subscription: %w", err)
+		}
+		if subscribed {
+			if flagVerbose {
+				fmt.Printf("address already subscribed: %s\n", sub.Address)
+			}
+			skipped++
+			continue
+		}
+		if err := nl.Config.CheckAllowed(sub.Address, flagConfirm); err != nil {
+			log.Printf("cannot import address %s: %v", sub.Address, err)
+			errCount++
+			continue
+		}
+
+		if flagConfirm {
+			time.Sleep(nl.SendInterval())
+			err = nl.Mailer.Send(nl.ConfirmSubscriptionMail(sub.Address, sub.Topics))
+		} else {
+			if sub.SubscribedAt.IsZero() {
+				sub.SubscribedAt = now
+			}
+			err = nl.Config.Subscribers.Add(&sub)
+		}
+		if err != nil {
+			log.Printf("cannot import address %s: %v", sub.Address, err)
+			errCount++
+			continue
+		}
+		entry := newsletter.JournalEntry{Event: newsletter.EventImport, Address: sub.Address, Outcome: newsletter.OutcomeOK}
+		if flagConfirm {
+			entry.Detail = "confirmation sent"
+		}
+		record(nl, entry)
+		if flagVerbose {
+			fmt.Printf("address imported: %s\n", sub.Address)
+		}
+		added++
+	}
+
+	if flagConfirm {
+		fmt.Printf("📨 %v confirmation email(s) sent, %v address(es) already subscribed\n", added, skipped)
+	} else {
+		fmt.Printf("📥 %v address(es) subscribed, %v address(es) already subscribed\n", added, skipped)
+	}
+	if errCount > 0 {
+		return fmt.Errorf("could not import %v address(es)", errCount)
+	}
+	return nil
+}
+
+func exportSubscribers(nl *newsletter.Newsletter, args []string) error {
+	var path string
+	switch len(args) {
+	case 0:
+	case 1:
+		path = args[0]
+	default:
+		return fmt.Errorf("too many arguments")
+	}
+
+	format, err := getFormat(path)
+	if err != nil {
+		return err
+	}
+	subs, err := nl.Config.Subscribers.List()
+	if err != nil {
+		return fmt.Errorf("list subscribers: %w", err)
+	}
+
+	out := os.Stdout
+	if path != "" {
+		out, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0660)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	if err := exchange.Write(out, format, subs); err != nil {
+		return fmt.Errorf("write %s subscribers: %w", format, err)
+	}
+	if path != "" {
+		if err := out.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("📤 %v address(es) exported to %q\n", len(subs), path)
+	}
+	return nil
+}
+
+func rotateSecret(nl *newsletter.Newsletter) error {
+	grace := nl.Config.Settings.ConfirmExpiryOrDefault()
+	if err := nl.Config.RotateSecret(grace); err != nil {
+		return err
+	}
+	record(nl, newsletter.JournalEntry{Event: newsletter.EventConfig, Outcome: newsletter.OutcomeOK, Detail: "secret rotated"})
+	fmt.Printf("🔑 secret rotated, the previous one is still accepted for %v\n", grace)
+	return nil
+}
+
+func config(args []string) error {
+	if len(args) != 1 || args[0] != "validate" {
+		return fmt.Errorf("expected \"validate\" sub command")
+	}
+	if flagList != "" {
+		if err := newsletter.ValidateListName(flagList); err != nil {
+			return err
+		}
+	}
+	dir, err := newsletter.ConfigDir(flagConfig, flagList)
+	if err != nil {
+		return err
+	}
+	valid := true
+	check := func(path string, err error) {
+		if err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				fmt.Printf("❌ %s: %s\n", path, line)
+			}
+			valid = false
+		} else {
+			fmt.Printf("✅ %s is valid\n", path)
+		}
+	}
+	if _, err := os.Stat(newsletter.SystemConfigPath); err == nil {
+		_, err = newsletter.LoadSystemConfig(newsletter.SystemConfigPath)
+		check(newsletter.SystemConfigPath, err)
+	}
+	path := filepath.Join(dir, newsletter.SettingsFile)
+	check(path, newsletter.ValidateSettingsFile(path))
+	if !valid {
+		return fmt.Errorf("invalid settings")
+	}
+	return nil
+}
+
+// record appends an event triggered by the command to the journal.
+func record(nl *newsletter.Newsletter, entry newsletter.JournalEntry) {
+	if err := nl.Record(entry); err != nil {
+		log.Printf("cannot record journal entry: %v", err)
+	}
+}
+
+// parseDate parses a date given as YYYY-MM-DD in local time, or in RFC 3339
+// format.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func showLog(nl *newsletter.Newsletter) error {
+	filter := newsletter.JournalFilter{Address: flagAddress, Event: flagEvent}
+	var err error
+	if flagSince != "" {
+		if filter.Since, err = parseDate(flagSince); err != nil {
+			return fmt.Errorf("invalid -since date: %w", err)
+		}
+	}
+	if flagUntil != "" {
+		if filter.Until, err = parseDate(flagUntil); err != nil {
+			return fmt.Errorf("invalid -until date: %w", err)
+		}
+	}
+	entries, err := nl.Journal(filter)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Time.Local().Format(time.DateTime), e.Event, e.Address, e.Outcome, e.Detail)
+	}
+	return w.Flush()
+}
+
+// pending lists the subscriptions awaiting approval and the issues awaiting
+// confirmation, approves or rejects the subscriptions of the given
+// addresses, or shows or discards the issues of the given IDs.
+func pending(nl *newsletter.Newsletter, args []string) error {
+	if len(args) == 0 {
+		return listPending(nl)
+	}
+
+	switch args[0] {
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("show needs exactly one issue ID")
+		}
+		return showIssue(nl, args[1])
+	case "discard":
+		if len(args) == 1 {
+			return fmt.Errorf("missing issue ID")
+		}
+		return discardIssues(nl, args[1:])
+	}
+
+	var event string
+	var moderate func(addr string) (*newsletter.PendingSubscription, error)
+	var notify func(p *newsletter.PendingSubscription) *mailer.Mail
+	switch args[0] {
+	case "approve":
+		event, moderate, notify = newsletter.EventApprove, nl.ApproveSubscription, nl.ApprovedMail
+	case "reject":
+		event, moderate, notify = newsletter.EventReject, nl.RejectSubscription, nl.RejectedMail
+	default:
+		return fmt.Errorf("unknown action %q, must be approve, reject, show or discard", args[0])
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("missing address")
+	}
+	errCount := 0
+	for _, addr := range args[1:] {
+		p, err := moderate(addr)
+		if err == nil {
+			err = nl.Mailer.Send(notify(p))
+		}
+		entry := newsletter.JournalEntry{Event: event, Address: addr, Outcome: newsletter.OutcomeOK}
+		if err != nil {
+			entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+		}
+		record(nl, entry)
+		if err != nil {
+			log.Printf("cannot %s subscription of %s: %v", args[0], addr, err)
+			errCount++
+			continue
+		}
+		if flagVerbose {
+			fmt.Printf("subscription %sd: %s\n", args[0], addr)
+		}
+	}
+	fmt.Printf("✅ %v subscription(s) %sd\n", len(args)-1-errCount, args[0])
+	if errCount > 0 {
+		return fmt.Errorf("%v subscription(s) could not be %sd", errCount, args[0])
+	}
+	return nil
+}
+
+// runQueue sends the queued issues whose sending time has come, or whose
+// sending was interrupted.
+func runQueue(nl *newsletter.Newsletter) error {
+	errCount := 0
+	for d, err := range nl.RunQueue() {
+		if err != nil {
+			log.Printf("%v", err)
+			errCount++
+			continue
+		}
+		fmt.Printf("✅ %q sent to %v subscribers with %v error(s)\n", d.Issue.Subject, len(d.Deliveries), len(d.Failures()))
+		if flagVerbose {
+			for _, f := range d.Failures() {
+				fmt.Printf("%s: %s\n", f.Address, f.Error)
+			}
+		}
+	}
+	if errCount > 0 {
+		return fmt.Errorf("%v issue(s) could not be sent", errCount)
+	}
+	return nil
+}
+
+// schedule lists the queued issues with their sending time, or cancels the
+// scheduled issues of the given IDs.
+func schedule(nl *newsletter.Newsletter, args []string) error {
+	if len(args) == 0 {
+		return listSchedule(nl)
+	}
+	switch args[0] {
+	case "list":
+		return listSchedule(nl)
+	case "cancel":
+		if len(args) == 1 {
+			return fmt.Errorf("missing issue ID")
+		}
+		return cancelScheduledIssues(nl, args[1:])
+	default:
+		return fmt.Errorf("unknown action %q, must be list or cancel", args[0])
+	}
+}
+
+func listSchedule(nl *newsletter.Newsletter) error {
+	issues, err := nl.QueuedIssues()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, i := range issues {
+		at := "now"
+		if !i.SendAt.IsZero() {
+			at = nl.FormatSendAt(i.SendAt)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", at, i.ID, i.State, i.Segment, i.Submitter, i.Subject)
+	}
+	return w.Flush()
+}
+
+// cancelScheduledIssues removes the queued issues of the given IDs, that
+// have not started to be sent.
+func cancelScheduledIssues(nl *newsletter.Newsletter, ids []string) error {
+	errCount := 0
+	for _, id := range ids {
+		entry := newsletter.JournalEntry{Event: newsletter.EventDiscard, Outcome: newsletter.OutcomeOK, Detail: "scheduled: " + id}
+		issue, err := nl.CancelScheduledIssue(id)
+		if issue != nil {
+			entry.Address = issue.Submitter
+		}
+		if err != nil {
+			entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+		}
+		record(nl, entry)
+		if err != nil {
+			log.Printf("cannot cancel issue %s: %v", id, err)
+			errCount++
+			continue
+		}
+		if flagVerbose {
+			fmt.Printf("issue cancelled: %s\n", id)
+		}
+	}
+	fmt.Printf("✅ %v issue(s) cancelled\n", len(ids)-errCount)
+	if errCount > 0 {
+		return fmt.Errorf("%v issue(s) could not be cancelled", errCount)
+	}
+	return nil
+}
+
+// suppress lists the suppression list, suppresses the given addresses as
+// bounced, or removes them from the list.
+func suppress(nl *newsletter.Newsletter, args []string) error {
+	if len(args) == 0 {
+		return listSuppressions(nl)
+	}
+	var event, done string
+	var action func(addr string) error
+	switch args[0] {
+	case "list":
+		return listSuppressions(nl)
+	case "bounced":
+		event, done, action = newsletter.EventBounce, "suppressed", nl.Config.Bounce
+	case "remove":
+		event, done, action = newsletter.EventUnsuppress, "unsuppressed", nl.Config.Unsuppress
+	default:
+		return fmt.Errorf("unknown action %q, must be list, bounced or remove", args[0])
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("missing address")
+	}
+	errCount := 0
+	for _, addr := range args[1:] {
+		err := action(addr)
+		entry := newsletter.JournalEntry{Event: event, Address: addr, Outcome: newsletter.OutcomeOK}
+		if err != nil {
+			entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+		}
+		record(nl, entry)
+		if err != nil {
+			log.Printf("address %s could not be %s: %v", addr, done, err)
+			errCount++
+			continue
+		}
+		if flagVerbose {
+			fmt.Printf("address %s: %s\n", done, addr)
+		}
+	}
+	fmt.Printf("✅ %v address(es) %s\n", len(args)-1-errCount, done)
+	if errCount > 0 {
+		return fmt.Errorf("%v address(es) could not be %s", errCount, done)
+	}
+	return nil
+}
+
+func listSuppressions(nl *newsletter.Newsletter) error {
+	suppressions, err := nl.Config.Suppressions()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, s := range suppressions {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.At.Local().Format(time.DateTime), s.Reason, s.Key)
+	}
+	return w.Flush()
+}
+
+func listPending(nl *newsletter.Newsletter) error {
+	list, err := nl.Config.PendingSubscriptions()
+	if err != nil {
+		return err
+	}
+	count, err := nl.CleanIssues()
+	if err != nil {
+		return err
+	}
+	if flagVerbose && count > 0 {
+		fmt.Printf("%v expired issue(s) removed\n", count)
+	}
+	issues, err := nl.Issues()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, p := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.RequestedAt.Local().Format(time.DateTime), p.Address, strings.Join(p.Topics, ","))
+	}
+	for _, i := range issues {
+		state := i.State
+		if state == newsletter.IssuePending {
+			state = "pending"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", i.CreatedAt.Local().Format(time.DateTime), i.ID, state, i.Submitter, i.Subject)
+	}
+	return w.Flush()
+}
+
+// showIssue prints the metadata and the text body of the pending issue of
+// the given ID.
+func showIssue(nl *newsletter.Newsletter, id string) error {
+	issue, err := nl.Issue(id)
+	if err != nil {
+		return err
+	}
+	body, err := nl.IssueBody(issue)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Subject:   %s\n", issue.Subject)
+	fmt.Printf("Submitter: %s\n", issue.Submitter)
+	fmt.Printf("Created:   %s\n", issue.CreatedAt.Local().Format(time.DateTime))
+	fmt.Printf("Expires:   %s\n", issue.ExpiresAt.Local().Format(time.DateTime))
+	if len(issue.Approvals) > 0 {
+		fmt.Printf("Approvals: %s\n", strings.Join(issue.Approvals, ", "))
+	}
+	if issue.Rich {
+		fmt.Println("Message:   HTML or files kept")
+	}
+	if !issue.SendAt.IsZero() {
+		fmt.Printf("Send at:   %s\n", nl.FormatSendAt(issue.SendAt))
+	}
+	if issue.State != newsletter.IssuePending {
+		fmt.Printf("State:     %s\n", issue.State)
+	}
+	if issue.Attempts > 0 && issue.State != newsletter.IssueSent {
+		fmt.Printf("Failed:    %v attempt(s), retry at %s\n", issue.Attempts, nl.FormatSendAt(issue.RetryAt))
+		fmt.Printf("Error:     %s\n", issue.LastError)
+	}
+	if !issue.SentAt.IsZero() {
+		fmt.Printf("Sent:      %s\n", issue.SentAt.Local().Format(time.DateTime))
+	}
+	fmt.Printf("\n%s\n", body)
+	return nil
+}
+
+// discardIssues removes the pending issues of the given IDs.
+func discardIssues(nl *newsletter.Newsletter, ids []string) error {
+	errCount := 0
+	for _, id := range ids {
+		entry := newsletter.JournalEntry{Event: newsletter.EventDiscard, Outcome: newsletter.OutcomeOK, Detail: id}
+		if issue, err := nl.Issue(id); err == nil {
+			entry.Address = issue.Submitter
+		}
+		err := nl.DiscardIssue(id)
+		if err != nil {
+			entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+		}
+		record(nl, entry)
+		if err != nil {
+			log.Printf("cannot discard issue %s: %v", id, err)
+			errCount++
+			continue
+		}
+		if flagVerbose {
+			fmt.Printf("issue discarded: %s\n", id)
+		}
+	}
+	fmt.Printf("✅ %v issue(s) discarded\n", len(ids)-errCount)
+	if errCount > 0 {
+		return fmt.Errorf("%v issue(s) could not be discarded", errCount)
+	}
+	return nil
+}
+
 const banner = "" +
 	"      __    __          __   /   __  _/_  _/_    __    __\n" +
 	"    /   ) /___)| /| /  (_ ` /  /___) /    /    /___) /   `\n" +
@@ -288,6 +1133,22 @@ const banner = "" +
 const usage = `
 Usage: newsletter [OPTION]... setup
        newsletter [OPTION]... send SUBJECT [CONTENT_FILE]
+       newsletter [OPTION]... add ADDRESS...
+       newsletter [OPTION]... remove ADDRESS...
+       newsletter [OPTION]... import [FILE]
+       newsletter [OPTION]... export [FILE]
+       newsletter [OPTION]... rotate-secret
+       newsletter [OPTION]... log
+       newsletter [OPTION]... pending [approve|reject ADDRESS...]
+       newsletter [OPTION]... pending show|discard ID...
+       newsletter [OPTION]... schedule [list|cancel ID...]
+       newsletter [OPTION]... suppress [list|bounced|remove ADDRESS...]
+       newsletter [OPTION]... run-queue
+       newsletter [OPTION]... config validate
+
+The config directory is ~/.config/newsletter, $XDG_CONFIG_HOME/newsletter
+if XDG_CONFIG_HOME is set, $NEWSLETTER_CONFIG_DIR if it is set, or DIR if
+-C DIR is given. Named lists are stored in its subdirectories.
 
 Options:`
 
@@ -312,6 +1173,17 @@ func main() {
 	flag.BoolVar(&flagVerbose, "v", false, "verbose: increase verbosity of program")
 	flag.BoolVar(&flagYes, "y", false, "yes: always answer yes when program ask for confirmation")
 	flag.BoolVar(&flagPreview, "p", false, "preview: limit to a preview (cannot by used with -y)")
+	flag.BoolVar(&flagConfirm, "c", false, "confirm: send a confirmation mail to imported addresses instead of subscribing them")
+	flag.BoolVar(&flagForce, "force", false, "force: add addresses even if they are suppressed, lifting their suppression")
+	flag.StringVar(&flagFormat, "f", "", "format: format of imported or exported subscribers (plain, csv, vcard or mbox), guessed from the file extension by default")
+	flag.StringVar(&flagSegment, "segment", "", "segment: only send to the subscribers of the given topic")
+	flag.StringVar(&flagAt, "at", "", "at: schedule the sending at the given time (YYYY-MM-DD HH:MM in the TimeZone setting, optionally followed by a time zone, or RFC 3339)")
+	flag.StringVar(&flagSince, "since", "", "since: only show the journal entries since the given date (YYYY-MM-DD or RFC 3339)")
+	flag.StringVar(&flagUntil, "until", "", "until: only show the journal entries before the given date (YYYY-MM-DD or RFC 3339)")
+	flag.StringVar(&flagAddress, "address", "", "address: only show the journal entries of the given address")
+	flag.StringVar(&flagEvent, "event", "", "event: only show the journal entries of the given event")
+	flag.StringVar(&flagList, "list", "", "list: name of the newsletter list to use instead of the default one")
+	flag.StringVar(&flagConfig, "C", "", "config: config directory of the default list, instead of ~/.config/newsletter")
 	flag.BoolVar(&flagHelp, "h", false, "shorthand for -help")
 	flag.BoolVar(&flagHelp, "help", false, "show help message")
 	flag.BoolVar(&flagVersion, "version", false, "show version")
@@ -337,12 +1209,28 @@ func main() {
 		help()
 	}
 
-	nl, err := newsletter.New()
+	if flagConfig != "" {
+		var err error
+		if flagConfig, err = filepath.Abs(flagConfig); err != nil {
+			log.Fatalf("config directory: %v", err)
+		}
+	}
+
+	// the config must be validated before it is loaded, as it cannot be
+	// if it is invalid
+	if args[0] == "config" {
+		if err := config(args[1:]); err != nil {
+			log.Fatalf("config error: %v", err)
+		}
+		return
+	}
+
+	nl, err := newsletter.NewListIn(flagConfig, flagList)
 	if err != nil {
 		log.Fatalf("init newsletter: %v", err)
 	}
 
-	messages.SetLanguage(nl.Config.Settings.Language)
+	messages.SetLanguage(nl.Config.LanguageOrDefault())
 
 	var cmdErr error
 
@@ -353,6 +1241,26 @@ func main() {
 		cmdErr = setup(nl)
 	case "send":
 		cmdErr = send(nl, args[1:])
+	case "add":
+		cmdErr = addSubscribers(nl, args[1:])
+	case "remove":
+		cmdErr = removeSubscribers(nl, args[1:])
+	case "import":
+		cmdErr = importSubscribers(nl, args[1:])
+	case "export":
+		cmdErr = exportSubscribers(nl, args[1:])
+	case "rotate-secret":
+		cmdErr = rotateSecret(nl)
+	case "log":
+		cmdErr = showLog(nl)
+	case "pending":
+		cmdErr = pending(nl, args[1:])
+	case "schedule":
+		cmdErr = schedule(nl, args[1:])
+	case "suppress":
+		cmdErr = suppress(nl, args[1:])
+	case "run-queue":
+		cmdErr = runQueue(nl)
 	default:
 		cmdlineFatalf("invalid sub command: %s", args[0])
 	}
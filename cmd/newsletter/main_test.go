@@ -24,6 +24,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
 )
 
 func assertFileMatch(t *testing.T, path string, expected string) {
@@ -44,11 +46,29 @@ func assertFileMatch(t *testing.T, path string, expected string) {
 
 }
 
-func TestInitForwardFiles(t *testing.T) {
+func fakeNewsletter(t *testing.T, list string) *newsletter.Newsletter {
+	t.Helper()
+	return &newsletter.Newsletter{
+		Config:    &newsletter.Config{Dir: t.TempDir()},
+		Hostname:  "club1.fr",
+		LocalUser: "user",
+		List:      list,
+	}
+}
+
+func setupHome(t *testing.T) string {
+	t.Helper()
 	homeDir := t.TempDir()
 	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(newsletter.ConfigDirEnv, "")
+	return homeDir
+}
+
+func TestInitForwardFiles(t *testing.T) {
+	homeDir := setupHome(t)
 
-	err := initForwardFiles()
+	err := initForwardFiles(fakeNewsletter(t, ""), "", newsletter.Routes[:])
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -64,3 +84,180 @@ func TestInitForwardFiles(t *testing.T) {
 		assertFileMatch(t, filepath.Join(homeDir, file), expected)
 	}
 }
+
+func TestInitForwardFilesList(t *testing.T) {
+	homeDir := setupHome(t)
+
+	err := initForwardFiles(fakeNewsletter(t, "digest"), "", append(newsletter.Routes[:], "subscribe-events"))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	expectedFiles := map[string]string{
+		".forward+digest-subscribe":         `^\| "/[\w/-]+/sbin/newsletterctl -list digest subscribe"\n$`,
+		".forward+digest-subscribe-confirm": `^\| "/[\w/-]+/sbin/newsletterctl -list digest subscribe-confirm"\n$`,
+		".forward+digest-unsubscribe":       `^\| "/[\w/-]+/sbin/newsletterctl -list digest unsubscribe"\n$`,
+		".forward+digest-send":              `^\| "/[\w/-]+/sbin/newsletterctl -list digest send"\n$`,
+		".forward+digest-send-confirm":      `^\| "/[\w/-]+/sbin/newsletterctl -list digest send-confirm"\n$`,
+		".forward+digest-subscribe-events":  `^\| "/[\w/-]+/sbin/newsletterctl -list digest subscribe-events"\n$`,
+	}
+	for file, expected := range expectedFiles {
+		assertFileMatch(t, filepath.Join(homeDir, file), expected)
+	}
+}
+
+func TestInitForwardFilesDelimiter(t *testing.T) {
+	homeDir := setupHome(t)
+	nl := fakeNewsletter(t, "digest")
+	nl.Config.Settings.Delimiter = "-"
+
+	err := initForwardFiles(nl, "", []string{newsletter.RouteSubscribe})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertFileMatch(t, filepath.Join(homeDir, ".forward-digest-subscribe"), `^\| "/[\w/-]+/sbin/newsletterctl -list digest subscribe"\n$`)
+	if _, err := os.Stat(filepath.Join(homeDir, ".forward+digest-subscribe")); !os.IsNotExist(err) {
+		t.Errorf("expected no forward file with the default delimiter, got: %v", err)
+	}
+
+	if err := stop(nl); err == nil {
+		t.Errorf("expected error for the forward files that were not written")
+	}
+	if _, err := os.Stat(filepath.Join(homeDir, ".forward-digest-subscribe")); !os.IsNotExist(err) {
+		t.Errorf("expected forward file to be removed by stop, got: %v", err)
+	}
+}
+
+func TestInitForwardFilesConfigDir(t *testing.T) {
+	cases := []struct {
+		name      string
+		configDir string
+		xdg       string
+		expected  string
+	}{
+		{"flag", "/srv/news letter", "", `^\| "/[\w/-]+/sbin/newsletterctl -C '/srv/news letter' -list digest send"\n$`},
+		{"xdg", "", "/srv/xdg", `^\| "/[\w/-]+/sbin/newsletterctl -C /srv/xdg/newsletter -list digest send"\n$`},
+		{"default", "", "", `^\| "/[\w/-]+/sbin/newsletterctl -list digest send"\n$`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			homeDir := setupHome(t)
+			t.Setenv("XDG_CONFIG_HOME", c.xdg)
+			if c.name == "default" {
+				c.configDir = filepath.Join(homeDir, newsletter.ConfigPath)
+			}
+
+			err := initForwardFiles(fakeNewsletter(t, "digest"), c.configDir, []string{newsletter.RouteSend})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			assertFileMatch(t, filepath.Join(homeDir, ".forward+digest-send"), c.expected)
+		})
+	}
+}
+
+func TestInitForwardFilesRewrite(t *testing.T) {
+	homeDir := setupHome(t)
+	filePath := filepath.Join(homeDir, ".forward+digest-send")
+	err := os.WriteFile(filePath, []byte(`| "/usr/local/sbin/newsletterctl -C /srv/old -list digest send"`+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nl := fakeNewsletter(t, "digest")
+	for range 2 {
+		err = initForwardFiles(nl, "/srv/new", []string{newsletter.RouteSend})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assertFileMatch(t, filePath, `^\| "/[\w/-]+/sbin/newsletterctl -C /srv/new -list digest send"\n$`)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("expected the mode of the rewritten file to be kept, got: %v", mode)
+	}
+}
+
+func TestIsListable(t *testing.T) {
+	cases := []struct {
+		mode     os.FileMode
+		expected bool
+	}{
+		{0700, false},
+		{0711, false},
+		{0750, true},
+		{0755, true},
+	}
+	for _, c := range cases {
+		t.Run(c.mode.String(), func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Chmod(dir, c.mode); err != nil {
+				t.Fatal(err)
+			}
+			if listable := isListable(dir); listable != c.expected {
+				t.Errorf("expected %v, got: %v", c.expected, listable)
+			}
+		})
+	}
+}
+
+func TestUpdateSendToken(t *testing.T) {
+	homeDir := setupHome(t)
+	nl := fakeNewsletter(t, "")
+
+	if err := updateSendToken(nl, sendTokenRotate); err != nil {
+		t.Fatalf("rotate: unexpected error: %v", err)
+	}
+	oldFile := filepath.Join(homeDir, forwardFileName(nl, nl.SendTokenRoute()))
+	if err := os.WriteFile(oldFile, nil, 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateSendToken(nl, sendTokenRotate); err != nil {
+		t.Fatalf("rotate again: unexpected error: %v", err)
+	}
+	if _, err := os.Stat(oldFile); !os.IsNotExist(err) {
+		t.Errorf("expected forward file of the previous token to be removed, got: %v", err)
+	}
+
+	nl.Config.Settings.RequireSendToken = true
+	if err := updateSendToken(nl, sendTokenRemove); err != nil {
+		t.Fatalf("remove: unexpected error: %v", err)
+	}
+	if nl.SendTokenAddr() != "" || nl.Config.Settings.RequireSendToken {
+		t.Errorf("expected send token to be removed and not required")
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	nl := fakeNewsletter(t, "")
+	store, err := newsletter.OpenFileStore(filepath.Join(nl.Config.Dir, newsletter.EmailsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nl.Config.Subscribers = store
+	if err := store.Add(&newsletter.Subscriber{Address: "bounce@club1.fr"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := suppress(nl, []string{"bounced", "bounce@club1.fr"}); err != nil {
+		t.Fatalf("bounced: unexpected error: %v", err)
+	}
+	if count, _ := store.Count(); count != 0 {
+		t.Errorf("expected bounced address to be removed, got %v subscribers", count)
+	}
+	assertFileMatch(t, filepath.Join(nl.Config.Dir, newsletter.SuppressionFile), "^bounce@club1.fr\tbounced\t")
+
+	if err := suppress(nl, []string{"remove", "bounce@club1.fr"}); err != nil {
+		t.Fatalf("remove: unexpected error: %v", err)
+	}
+	if err := nl.Config.CheckAllowed("bounce@club1.fr", false); err != nil {
+		t.Errorf("expected removed address to be allowed, got: %v", err)
+	}
+	if err := suppress(nl, []string{"unknown", "bounce@club1.fr"}); err == nil {
+		t.Errorf("expected error for an unknown action")
+	}
+}
@@ -25,20 +25,29 @@ import (
 	"log"
 	"os"
 
+	"github.com/club-1/newsletter-go/v3"
 	"github.com/club-1/newsletter-go/v3/control"
 )
 
 const CmdName = "newsletterctl"
 
+// exTempFail is the exit code of temporary failures, from sysexits.h, that
+// makes the MTA keep the mail in its queue and retry its delivery later.
+const exTempFail = 75
+
 // Set by the compiler
 var version = "unknown"
 
 var (
 	flagVersion bool
+	flagList    string
+	flagConfig  string
 )
 
 func main() {
 	flag.BoolVar(&flagVersion, "version", false, "show version")
+	flag.StringVar(&flagList, "list", "", "name of the newsletter list to use instead of the default one")
+	flag.StringVar(&flagConfig, "C", "", "config directory of the default list, instead of ~/.config/newsletter")
 	flag.Parse()
 
 	if flagVersion {
@@ -51,9 +60,30 @@ func main() {
 		log.Fatal("missing sub command")
 	}
 
-	controller, err := control.NewController()
+	if flagList != "" {
+		if err := newsletter.ValidateListName(flagList); err != nil {
+			log.Fatalln("error:", err)
+		}
+	}
+
+	controller, err := control.NewListControllerIn(flagConfig, flagList)
 	if err != nil {
-		log.Fatalln("error:", err)
+		if args[0] == control.RouteRunQueue {
+			log.Fatalln("error:", err)
+		}
+		// The remaining errors, like invalid settings or an unavailable
+		// syslog, are fixed by the owner or transient, so the mail is
+		// deferred rather than lost. The error is printed for the logs of
+		// the MTA, in addition to syslog when it could be opened.
+		log.Println("error:", err)
+		os.Exit(exTempFail)
+	}
+
+	if args[0] == control.RouteRunQueue {
+		if err := controller.RunQueue(); err != nil {
+			log.Fatalln("error:", err)
+		}
+		return
 	}
 
 	cmdErr := controller.Handle(args[0], os.Stdin)
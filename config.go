@@ -29,17 +29,26 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"slices"
 	"strings"
+	"time"
 
 	"github.com/club-1/newsletter-go/v3/messages"
 )
 
 const (
-	EmailsFile    string = "emails"
-	SecretFile    string = ".secret"
-	SignatureFile string = "signature.txt"
-	SettingsFile  string = "settings.json"
+	BoltFile           string = "subscribers.db"
+	EmailsFile         string = "emails"
+	SecretFile         string = ".secret"
+	SignatureFile      string = "signature.txt"
+	SettingsFile       string = "settings.json"
+	SuppressionFile    string = "suppressed"
+	ThrottleFile       string = ".throttle"
+	NoticeThrottleFile string = ".throttle-notices"
+	JournalFile        string = "journal.jsonl"
+	PendingFile        string = "pending.jsonl"
+	SendTokenFile      string = ".send-token"
+	IssuesDir          string = ".issues"
+	AdminDir           string = ".admin"
 )
 
 // Some error values.
@@ -48,40 +57,156 @@ var (
 )
 
 type Settings struct {
+	// Version is the version of the settings schema, see [SettingsVersion].
+	Version     int `json:",omitempty"`
 	Title       string
 	DisplayName string
 	Language    messages.Language
+	// Store is the subscriber store backend, either [StoreFile] (the
+	// default) or [StoreBolt].
+	Store string `json:",omitempty"`
+	// Domain is the domain of the addresses of the list, the hostname of
+	// the server by default.
+	Domain string `json:",omitempty"`
+	// LocalPart is the local part of the addresses of the list, the name
+	// of the user by default.
+	LocalPart string `json:",omitempty"`
+	// Delimiter is the recipient delimiter of the MTA, "+" by default.
+	Delimiter string `json:",omitempty"`
+	// Topics are the topics that subscribers can choose from.
+	Topics []string `json:",omitempty"`
+	// ConfirmExpiry is the validity of the confirmation requests,
+	// [DefaultConfirmExpiry] if not set.
+	ConfirmExpiry Duration `json:",omitzero"`
+	// LegacyTokensUntil is the end of the compatibility window during
+	// which confirmation IDs generated by previous versions are accepted.
+	LegacyTokensUntil time.Time `json:",omitzero"`
+	// HashSuppressions makes the suppression list store salted hashes of
+	// the addresses instead of the addresses themselves.
+	HashSuppressions bool `json:",omitempty"`
+	// BlockedDomains are domains whose addresses cannot be subscribed,
+	// including their subdomains.
+	BlockedDomains []string `json:",omitempty"`
+	// BlockedPatterns are regular expressions matching addresses that
+	// cannot be subscribed.
+	BlockedPatterns []string `json:",omitempty"`
+	// Throttle limits the confirmation mails sent in reply to subscription
+	// requests.
+	Throttle ThrottleSettings `json:",omitzero"`
+	// AuthServID is the authserv-id of the Authentication-Results header
+	// fields added by the local MTA, the only ones that are trusted.
+	AuthServID string `json:",omitempty"`
+	// RequireAuth maps routes to the authentication methods ("dmarc",
+	// "dkim" or "spf") that a request must pass, at least one of them,
+	// according to the trusted Authentication-Results.
+	RequireAuth map[string][]string `json:",omitempty"`
+	// SubscriptionPolicy is who can subscribe by email, [SubscriptionOpen]
+	// (the default), [SubscriptionApprove] or [SubscriptionInvite].
+	SubscriptionPolicy string `json:",omitempty"`
+	// Editors are the addresses allowed to send news by email, in addition
+	// to the address of the owner.
+	Editors []string `json:",omitempty"`
+	// SendApprovals is the number of editors, including the owner, that
+	// must confirm the news sent by email before it is distributed, 1 if
+	// not set.
+	SendApprovals int `json:",omitempty"`
+	// RequireSendToken rejects the news sent by email to the plain send
+	// address, only accepting the ones sent to the secret send address.
+	RequireSendToken bool `json:",omitempty"`
+	// SendKeyword and CancelKeyword are the keywords of the replies to the
+	// previews, that send or cancel the news. They are localized by
+	// default.
+	SendKeyword   string `json:",omitempty"`
+	CancelKeyword string `json:",omitempty"`
+	// TimeZone is the IANA name of the time zone of the sending times that
+	// are given without one, like "Europe/Paris". It is the local time
+	// zone of the server by default.
+	TimeZone string `json:",omitempty"`
+}
+
+// ConfirmExpiryOrDefault returns [Settings.ConfirmExpiry], or
+// [DefaultConfirmExpiry] if it is not set.
+func (s *Settings) ConfirmExpiryOrDefault() time.Duration {
+	if s.ConfirmExpiry > 0 {
+		return time.Duration(s.ConfirmExpiry)
+	}
+	return DefaultConfirmExpiry
+}
+
+// Duration is a [time.Duration] that is encoded as a string like "72h" in
+// JSON.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
 }
 
 type Config struct {
-	Dir       string
-	Emails    []string
-	Secret    string
+	Dir         string
+	Subscribers SubscriberStore
+	Secret      string
+	// OldSecrets are the previous secrets, still accepted until they retire.
+	OldSecrets []OldSecret
+	// SendToken is the secret token of the send address, empty if there
+	// is none.
+	SendToken string
 	Signature string
 	Settings  Settings
+	// System is the system-wide config of the server.
+	System SystemConfig
 }
 
+// LanguageOrDefault returns [Settings.Language], or the default language
+// of the system configuration if the list does not set one.
+func (c *Config) LanguageOrDefault() messages.Language {
+	if c.Settings.Language != "" {
+		return c.Settings.Language
+	}
+	return c.System.Defaults.Language
+}
+
+// Unsubscribe removes the given address from the subscribers, and
+// suppresses it so that it cannot be subscribed again without an explicit
+// opt-in.
 func (c *Config) Unsubscribe(addr string) error {
-	index := slices.Index(c.Emails, addr)
-	if index == -1 {
-		return ErrNotSubscribed
+	if err := c.Subscribers.Remove(addr); err != nil {
+		return err
+	}
+	return c.Suppress(addr, SuppressUnsubscribed)
+}
+
+// IsSubscribed reports whether the given address is subscribed.
+func (c *Config) IsSubscribed(addr string) (bool, error) {
+	_, err := c.Subscribers.Get(addr)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotSubscribed):
+		return false, nil
+	default:
+		return false, err
 	}
-	c.Emails = append(c.Emails[:index], c.Emails[index+1:]...)
-	return c.saveEmails()
 }
 
 func (c *Config) Subscribe(addr string) error {
-	c.Emails = append(c.Emails, addr)
-	return c.saveEmails()
+	return c.Subscribers.Add(&Subscriber{
+		Address:      addr,
+		SubscribedAt: time.Now().UTC().Truncate(time.Second),
+	})
 }
 
-func (c *Config) saveEmails() error {
-	emailsFilePath := filepath.Join(c.Dir, EmailsFile)
-	err := writeLines(c.Emails, emailsFilePath)
-	if err != nil {
-		return fmt.Errorf("could not save emails: %w", err)
-	}
-	return nil
+// Close releases the resources held by the config.
+func (c *Config) Close() error {
+	return c.Subscribers.Close()
 }
 
 func (c *Config) SaveSignature() error {
@@ -118,6 +243,27 @@ func readLines(path string) ([]string, error) {
 	return lines, scanner.Err()
 }
 
+// writeFileAtomic writes data to a temporary file that then replaces the
+// file at the given path, so that readers never see a partial file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // writeLines writes the lines to the given file.
 func writeLines(lines []string, path string) error {
 	content := strings.Join(lines, "\n")
@@ -155,16 +301,9 @@ func InitConfig(configDir string) (*Config, error) {
 		return nil, fmt.Errorf("init config directory: %w", err)
 	}
 
-	var emails []string
-	emailsFilePath := filepath.Join(configDir, EmailsFile)
-	_, err = os.Stat(emailsFilePath)
-	if errors.Is(err, os.ErrNotExist) {
-		emails = []string{}
-	} else {
-		emails, err = readLines(emailsFilePath)
-		if err != nil {
-			return nil, fmt.Errorf("get emails: %w", err)
-		}
+	system, err := LoadSystemConfig(SystemConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("load system config %s: %w", SystemConfigPath, err)
 	}
 
 	var signature string
@@ -181,6 +320,7 @@ func InitConfig(configDir string) (*Config, error) {
 	}
 
 	var secret string
+	var oldSecrets []OldSecret
 	secretFilePath := filepath.Join(configDir, SecretFile)
 	_, err = os.Stat(secretFilePath)
 	if errors.Is(err, os.ErrNotExist) {
@@ -195,14 +335,26 @@ func InitConfig(configDir string) (*Config, error) {
 		if err != nil {
 			return nil, fmt.Errorf("get secret: %w", err)
 		}
-		secret = strings.TrimSpace(string(secretB))
+		secret, oldSecrets, err = parseSecrets(string(secretB))
+		if err != nil {
+			return nil, fmt.Errorf("decode secrets: %w", err)
+		}
+	}
+
+	sendToken, err := readSendToken(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("get send token: %w", err)
 	}
 
 	var settings Settings
 	settingsFilePath := filepath.Join(configDir, SettingsFile)
 	_, err = os.Stat(settingsFilePath)
 	if errors.Is(err, os.ErrNotExist) {
-		settings = Settings{}
+		// There is no legacy token to accept on a fresh install.
+		settings = Settings{
+			Version:           SettingsVersion,
+			LegacyTokensUntil: time.Now().UTC().Truncate(time.Second),
+		}
 		if err := saveSettings(settingsFilePath, settings); err != nil {
 			return nil, fmt.Errorf("init settings: %w", err)
 		}
@@ -211,17 +363,41 @@ func InitConfig(configDir string) (*Config, error) {
 		if err != nil {
 			return nil, fmt.Errorf("get settings: %w", err)
 		}
-		err = json.Unmarshal(settingsJson, &settings)
+		settings, err = decodeSettings(settingsJson)
 		if err != nil {
 			return nil, fmt.Errorf("decode settings: %w", err)
 		}
+		migrated, err := settings.Migrate()
+		if err != nil {
+			return nil, fmt.Errorf("migrate settings: %w", err)
+		}
+		if migrated {
+			if err := saveSettings(settingsFilePath, settings); err != nil {
+				return nil, fmt.Errorf("migrate settings: %w", err)
+			}
+			log.Printf("settings migrated to version %d", settings.Version)
+		}
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+
+	subscribers, err := OpenStore(settings.Store, configDir)
+	if err != nil {
+		return nil, fmt.Errorf("get subscribers: %w", err)
+	}
+	if max := system.Policy.MaxSubscribers; max > 0 {
+		subscribers = &limitedStore{SubscriberStore: subscribers, max: max}
 	}
 
 	return &Config{
-		Dir:       configDir,
-		Emails:    emails,
-		Signature: signature,
-		Secret:    secret,
-		Settings:  settings,
+		Dir:         configDir,
+		Subscribers: subscribers,
+		Signature:   signature,
+		Secret:      secret,
+		OldSecrets:  oldSecrets,
+		SendToken:   sendToken,
+		Settings:    settings,
+		System:      system,
 	}, nil
 }
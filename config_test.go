@@ -24,49 +24,57 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/club-1/newsletter-go/v3"
 	"github.com/club-1/newsletter-go/v3/messages"
 )
 
+var legacyTokensUntil = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func TestInitConfig(t *testing.T) {
 	cases := []struct {
-		name     string
-		expected *newsletter.Config
+		name          string
+		expectedAddrs []string
+		expected      *newsletter.Config
 	}{
 		{
 			"basic",
+			[]string{},
 			&newsletter.Config{
-				Emails: []string{},
 				Secret: "BASIC_SECRET",
 				Settings: newsletter.Settings{
-					Title:       "Title",
-					DisplayName: "Display Name",
-					Language:    messages.LangFrench,
+					Version:           newsletter.SettingsVersion,
+					Title:             "Title",
+					DisplayName:       "Display Name",
+					Language:          messages.LangFrench,
+					LegacyTokensUntil: legacyTokensUntil,
 				},
 			},
 		},
 		{
 			"with_emails",
+			[]string{"coucou@club1.fr", "test@example.com"},
 			&newsletter.Config{
-				Emails: []string{"coucou@club1.fr", "test@example.com"},
 				Secret: "BASIC_SECRET",
 				Settings: newsletter.Settings{
-					Title:       "Title",
-					DisplayName: "Display Name",
-					Language:    messages.LangFrench,
+					Version:           newsletter.SettingsVersion,
+					Title:             "Title",
+					DisplayName:       "Display Name",
+					Language:          messages.LangFrench,
+					LegacyTokensUntil: legacyTokensUntil,
 				},
 			},
 		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			subTestInitConfig(t, c.name, c.expected)
+			subTestInitConfig(t, c.name, c.expectedAddrs, c.expected)
 		})
 	}
 }
 
-func subTestInitConfig(t *testing.T, name string, expected *newsletter.Config) {
+func subTestInitConfig(t *testing.T, name string, expectedAddrs []string, expected *newsletter.Config) {
 	configDir, err := filepath.Abs("testdata/config_" + name)
 	if err != nil {
 		t.Fatal(err)
@@ -74,8 +82,16 @@ func subTestInitConfig(t *testing.T, name string, expected *newsletter.Config) {
 	expected.Dir = configDir
 	config, err := newsletter.InitConfig(configDir)
 	if err != nil {
-		t.Errorf("init config: unexpected error: %v", err)
+		t.Fatalf("init config: unexpected error: %v", err)
+	}
+	addrs, err := newsletter.Addresses(config.Subscribers)
+	if err != nil {
+		t.Errorf("list subscribers: unexpected error: %v", err)
 	}
+	if !reflect.DeepEqual(expectedAddrs, addrs) {
+		t.Errorf("expected subscribers:\n%#v\ngot:\n%#v", expectedAddrs, addrs)
+	}
+	expected.Subscribers = config.Subscribers
 	if !reflect.DeepEqual(expected, config) {
 		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, config)
 	}
@@ -134,3 +150,22 @@ func subTestSaveSettings(t *testing.T, settings newsletter.Settings, expected st
 		t.Errorf("expected:\n%s\ngot:\n%s", expected, content)
 	}
 }
+
+func TestInitConfigUpgrade(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmpDir, newsletter.SettingsFile), []byte(`{"Title":"Title","ConfirmExpiry":"1h"}`), 0660)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := newsletter.InitConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	until := time.Until(config.Settings.LegacyTokensUntil)
+	if until <= 0 || until > time.Hour {
+		t.Errorf("expected legacy tokens to be accepted for about 1h, got: %v", until)
+	}
+	if config.Settings.Version != newsletter.SettingsVersion {
+		t.Errorf("expected settings to be migrated to version %d, got: %d", newsletter.SettingsVersion, config.Settings.Version)
+	}
+}
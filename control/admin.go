@@ -0,0 +1,322 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package control
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// Commands of the admin route, one per line in the body of the request.
+const (
+	AdminList     = "list"
+	AdminCount    = "count"
+	AdminAdd      = "add"
+	AdminRemove   = "remove"
+	AdminStats    = "stats"
+	AdminSettings = "settings"
+)
+
+// adminStatsPeriod is the period covered by the journal part of the stats.
+const adminStatsPeriod = 30 * 24 * time.Hour
+
+// errUnknownCommand is returned for lines that are not admin commands.
+var errUnknownCommand = errors.New("unknown command")
+
+// adminCommands returns the command lines of the given body, stopping at
+// the signature and skipping empty and quoted lines.
+func adminCommands(body string) []string {
+	var lines []string
+	for line := range strings.Lines(body) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "-- " {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ">") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// isAdminChange reports whether the command line changes the list, and
+// must thus be confirmed by the owner before being run.
+func isAdminChange(line string) bool {
+	name, _, _ := strings.Cut(line, " ")
+	switch strings.ToLower(name) {
+	case AdminAdd, AdminRemove, AdminSettings:
+		return true
+	}
+	return false
+}
+
+// admin runs the commands sent by the owner and replies with a report. As
+// the From header alone can be forged, the commands that change the list
+// are only run once the owner confirms them by replying to the report.
+func (c *Controller) admin(req *Request) error {
+	if req.From.Address != c.nl.LocalUserAddr() {
+		return fmt.Errorf("email From doesn't match user address")
+	}
+	if len(req.Headers.InReplyTo) > 0 {
+		id, err := c.nl.VerifyAdminID(string(req.Headers.InReplyTo[0]))
+		if errors.Is(err, newsletter.ErrExpiredToken) {
+			return fmt.Errorf("In-Reply-To verification error: %w", err)
+		}
+		if err == nil {
+			return c.adminConfirm(req, id)
+		}
+	}
+
+	lines := adminCommands(bodyText(&req.Email))
+	if slices.ContainsFunc(lines, isAdminChange) {
+		return c.adminRequestConfirmation(req, lines)
+	}
+	return c.runAdminCommands(req, lines)
+}
+
+// adminRequestConfirmation stores the command lines and asks the owner to
+// confirm them, in a report whose Message-ID is bound to the owner.
+func (c *Controller) adminRequestConfirmation(req *Request, lines []string) error {
+	id, err := c.nl.SaveAdminCommands(lines)
+	if err != nil {
+		return fmt.Errorf("save admin commands: %w", err)
+	}
+	c.entry.Detail = fmt.Sprintf("%v commands awaiting confirmation", len(lines))
+
+	report := strings.Builder{}
+	for _, line := range lines {
+		fmt.Fprintf(&report, "> %s\n", line)
+	}
+	fmt.Fprintf(&report, messages.AdminConfirm_line.Print(),
+		c.nl.ActionKeyword(newsletter.ActionApprove), c.nl.ActionKeyword(newsletter.ActionCancel))
+	mail := c.response(req, messages.AdminConfirm_subject.Print(), report.String())
+	mail.Id = fmt.Sprintf("<%s>", c.nl.AdminID(id))
+	if err := c.nl.Mailer.Send(mail); err != nil {
+		return fmt.Errorf("send confirmation mail: %w", err)
+	}
+	c.log.Infof("admin confirmation requested for %v commands", len(lines))
+	return nil
+}
+
+// adminConfirm runs or drops the pending commands whose report the owner
+// replied to, according to the keyword of the reply. Replies without
+// keyword, like auto-replies, keep the commands pending.
+func (c *Controller) adminConfirm(req *Request, id string) error {
+	action := c.nl.ConfirmAction(bodyText(&req.Email), newsletter.ActionApprove, newsletter.ActionCancel)
+	if action == "" {
+		approve, cancel := c.nl.ActionKeyword(newsletter.ActionApprove), c.nl.ActionKeyword(newsletter.ActionCancel)
+		c.reject("no %s or %s keyword in the admin confirmation", approve, cancel)
+		c.sendResponse(req, messages.AdminNoKeyword_subject.Print(), fmt.Sprintf(messages.AdminNoKeyword_body.Print(), approve, cancel))
+		return nil
+	}
+	lines, err := c.nl.TakeAdminCommands(id)
+	if err != nil {
+		return fmt.Errorf("take admin commands: %w", err)
+	}
+	if action == newsletter.ActionCancel {
+		c.entry.Detail = fmt.Sprintf("%v commands cancelled", len(lines))
+		c.log.Infof("admin commands cancelled")
+		c.sendResponse(req, messages.AdminCancelled_subject.Print(), messages.AdminCancelled_body.Print())
+		return nil
+	}
+	return c.runAdminCommands(req, lines)
+}
+
+// runAdminCommands runs the command lines and replies with a report.
+func (c *Controller) runAdminCommands(req *Request, lines []string) error {
+	report := strings.Builder{}
+	failed := 0
+	for _, line := range lines {
+		fmt.Fprintf(&report, "> %s\n", line)
+		out, err := c.adminCommand(req, line)
+		if err != nil {
+			fmt.Fprintf(&report, messages.AdminError_line.Print(), err)
+			failed++
+			continue
+		}
+		report.WriteString(out + "\n")
+	}
+	if len(lines) == 0 || failed > 0 {
+		report.WriteString(messages.AdminHelp_body.Print())
+	}
+	c.entry.Detail = fmt.Sprintf("%v commands, %v failed", len(lines), failed)
+
+	mail := c.response(req, messages.AdminReport_subject.Print(), strings.TrimSpace(report.String()))
+	if err := c.nl.Mailer.Send(mail); err != nil {
+		return fmt.Errorf("send report mail: %w", err)
+	}
+	c.log.Infof("admin report sent for %v commands, %v failed", len(lines), failed)
+	return nil
+}
+
+// adminCommand runs a single command line and returns its output.
+func (c *Controller) adminCommand(req *Request, line string) (string, error) {
+	name, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+	switch strings.ToLower(name) {
+	case AdminList:
+		addrs, err := newsletter.Addresses(c.nl.Config.Subscribers)
+		if err != nil {
+			return "", fmt.Errorf("list subscribers: %w", err)
+		}
+		if len(addrs) == 0 {
+			return messages.AdminNoSubscribers_line.Print(), nil
+		}
+		return strings.Join(addrs, "\n") + "\n", nil
+	case AdminCount:
+		count, err := c.nl.Config.Subscribers.Count()
+		if err != nil {
+			return "", fmt.Errorf("count subscribers: %w", err)
+		}
+		return fmt.Sprintf(messages.AdminCount_line.Print(), count), nil
+	case AdminAdd:
+		return c.adminAdd(req, args)
+	case AdminRemove:
+		return c.adminRemove(req, args)
+	case AdminStats:
+		return c.adminStats()
+	case AdminSettings:
+		return c.adminSettings(req, args)
+	default:
+		return "", fmt.Errorf("%w %q", errUnknownCommand, name)
+	}
+}
+
+// now returns the current time of the newsletter.
+func (c *Controller) now() time.Time {
+	if c.nl.Now != nil {
+		return c.nl.Now()
+	}
+	return time.Now()
+}
+
+// recordChange records a change made by a request of the owner in the
+// journal, in addition to the entry of the request itself.
+func (c *Controller) recordChange(req *Request, event string, addr string, detail string, err error) {
+	entry := newsletter.JournalEntry{
+		Event:     event,
+		Route:     c.entry.Route,
+		Address:   addr,
+		MessageID: req.MessageID,
+		Outcome:   newsletter.OutcomeOK,
+		Detail:    detail,
+	}
+	if err != nil {
+		entry.Outcome, entry.Detail = newsletter.OutcomeError, err.Error()
+	}
+	if err := c.nl.Record(entry); err != nil {
+		c.log.Errorf("record journal entry: %v", err)
+	}
+}
+
+func (c *Controller) adminAdd(req *Request, addr string) (string, error) {
+	if addr == "" || strings.ContainsAny(addr, " \t") {
+		return "", fmt.Errorf("expected a single address")
+	}
+	err := c.nl.Config.CheckAllowed(addr, false)
+	if err == nil {
+		sub := &newsletter.Subscriber{Address: addr, SubscribedAt: c.now().UTC().Truncate(time.Second)}
+		err = c.nl.Config.Subscribers.Add(sub)
+	}
+	c.recordChange(req, newsletter.EventAdd, addr, "", err)
+	if err != nil {
+		return "", fmt.Errorf("cannot add address %s: %w", addr, err)
+	}
+	c.log.Infof("address %q added by admin command", addr)
+	return fmt.Sprintf(messages.AdminAdded_line.Print(), addr), nil
+}
+
+func (c *Controller) adminRemove(req *Request, addr string) (string, error) {
+	if addr == "" || strings.ContainsAny(addr, " \t") {
+		return "", fmt.Errorf("expected a single address")
+	}
+	err := c.nl.Config.Remove(addr)
+	c.recordChange(req, newsletter.EventRemove, addr, "", err)
+	if err != nil {
+		return "", fmt.Errorf("cannot remove address %s: %w", addr, err)
+	}
+	c.log.Infof("address %q removed by admin command", addr)
+	return fmt.Sprintf(messages.AdminRemoved_line.Print(), addr), nil
+}
+
+func (c *Controller) adminStats() (string, error) {
+	out := strings.Builder{}
+	count, err := c.nl.Config.Subscribers.Count()
+	if err != nil {
+		return "", fmt.Errorf("count subscribers: %w", err)
+	}
+	fmt.Fprintf(&out, messages.AdminStatsSubscribers_line.Print(), count)
+	for _, topic := range c.nl.Config.Settings.Topics {
+		count, err := c.nl.CountSegment(topic)
+		if err != nil {
+			return "", fmt.Errorf("count subscribers of topic %q: %w", topic, err)
+		}
+		fmt.Fprintf(&out, messages.AdminStatsTopic_line.Print(), topic, count)
+	}
+	suppressions, err := c.nl.Config.Suppressions()
+	if err != nil {
+		return "", fmt.Errorf("list suppressions: %w", err)
+	}
+	fmt.Fprintf(&out, messages.AdminStatsSuppressed_line.Print(), len(suppressions))
+
+	entries, err := c.nl.Journal(newsletter.JournalFilter{Since: c.now().Add(-adminStatsPeriod)})
+	if err != nil {
+		return "", fmt.Errorf("read journal: %w", err)
+	}
+	events := []string{newsletter.RouteSubscribeConfirm, newsletter.RouteUnSubscribe, newsletter.RouteSendConfirm}
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.Outcome == newsletter.OutcomeOK {
+			counts[e.Event]++
+		}
+	}
+	fmt.Fprintf(&out, messages.AdminStatsPeriod_line.Print(), int(adminStatsPeriod.Hours()/24))
+	for _, event := range events {
+		fmt.Fprintf(&out, "    %s: %v\n", event, counts[event])
+	}
+	return out.String(), nil
+}
+
+func (c *Controller) adminSettings(req *Request, args string) (string, error) {
+	name, value, _ := strings.Cut(args, " ")
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(name) {
+	case "title":
+		c.nl.Config.Settings.Title = value
+	default:
+		return "", fmt.Errorf("unknown setting %q", name)
+	}
+	err := c.nl.Config.SaveSettings()
+	detail := fmt.Sprintf("%s: %q", strings.ToLower(name), value)
+	c.recordChange(req, newsletter.EventConfig, "", detail, err)
+	if err != nil {
+		return "", err
+	}
+	c.log.Infof("setting changed by admin command: %s", detail)
+	return fmt.Sprintf(messages.AdminSettingSet_line.Print(), strings.ToLower(name), value), nil
+}
@@ -0,0 +1,162 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package control
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+// ErrUnauthenticated is returned when a request does not pass the
+// authentication methods required for its route.
+var ErrUnauthenticated = errors.New("unauthenticated request")
+
+// AuthResult is the result of an authentication method, as found in an
+// Authentication-Results header field defined by RFC 8601.
+type AuthResult struct {
+	Method string
+	Result string
+	// Properties are the properties of the result, like "header.d".
+	Properties map[string]string
+}
+
+func (r AuthResult) String() string {
+	return r.Method + "=" + r.Result
+}
+
+var authCommentRegexp = regexp.MustCompile(`\([^()]*\)`)
+
+// ParseAuthResults parses the value of an Authentication-Results header
+// field, and returns its authserv-id and results.
+func ParseAuthResults(value string) (string, []AuthResult) {
+	value = authCommentRegexp.ReplaceAllString(value, " ")
+	parts := strings.Split(value, ";")
+	authservID, _, _ := strings.Cut(strings.TrimSpace(parts[0]), " ")
+
+	var results []AuthResult
+	for _, part := range parts[1:] {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		method, result, found := strings.Cut(fields[0], "=")
+		if !found {
+			// "none" means that no method was applied
+			continue
+		}
+		method, _, _ = strings.Cut(method, "/")
+		r := AuthResult{
+			Method:     strings.ToLower(method),
+			Result:     strings.ToLower(result),
+			Properties: make(map[string]string),
+		}
+		for _, field := range fields[1:] {
+			key, value, found := strings.Cut(field, "=")
+			if found {
+				r.Properties[strings.ToLower(key)] = strings.Trim(value, `"`)
+			}
+		}
+		results = append(results, r)
+	}
+	return authservID, results
+}
+
+// AuthResults returns the authentication results of the request that were
+// added by the server with the given authserv-id.
+func (r *Request) AuthResults(authservID string) []AuthResult {
+	var results []AuthResult
+	for _, value := range r.Headers.ExtraHeaders["Authentication-Results"] {
+		id, res := ParseAuthResults(value)
+		if strings.EqualFold(id, authservID) {
+			results = append(results, res...)
+		}
+	}
+	return results
+}
+
+// aligned reports whether the domain is the From domain or one of its
+// parent domains, top-level domains excluded.
+func aligned(domain, fromDomain string) bool {
+	domain = strings.ToLower(domain)
+	return strings.Contains(domain, ".") && (fromDomain == domain || strings.HasSuffix(fromDomain, "."+domain))
+}
+
+// passes reports whether the result is a pass of the given method that
+// authenticates the From domain.
+func (r AuthResult) passes(method string, fromDomain string) bool {
+	if r.Method != method || r.Result != "pass" {
+		return false
+	}
+	switch method {
+	case newsletter.AuthDMARC:
+		// a result that does not tell the domain it was checked against
+		// is not aligned
+		from := r.Properties["header.from"]
+		return from != "" && strings.EqualFold(from, fromDomain)
+	case newsletter.AuthDKIM:
+		domain := r.Properties["header.d"]
+		if domain == "" {
+			_, domain, _ = strings.Cut(r.Properties["header.i"], "@")
+		}
+		return aligned(domain, fromDomain)
+	case newsletter.AuthSPF:
+		mailfrom := r.Properties["smtp.mailfrom"]
+		if i := strings.LastIndex(mailfrom, "@"); i >= 0 {
+			mailfrom = mailfrom[i+1:]
+		}
+		return aligned(mailfrom, fromDomain)
+	}
+	return false
+}
+
+// checkAuth returns an error wrapping [ErrUnauthenticated] if the request
+// does not pass any of the authentication methods required for the route
+// by the settings.
+func (c *Controller) checkAuth(route string, req *Request) error {
+	required := c.nl.Config.Settings.RequireAuth[route]
+	if len(required) == 0 {
+		return nil
+	}
+	authservID := c.nl.Config.Settings.AuthServID
+	if authservID == "" {
+		return fmt.Errorf("%w: %s required but no AuthServID is configured", ErrUnauthenticated, strings.Join(required, " or "))
+	}
+	_, fromDomain, _ := strings.Cut(strings.ToLower(req.From.Address), "@")
+	results := req.AuthResults(authservID)
+	for _, r := range results {
+		if slices.ContainsFunc(required, func(method string) bool { return r.passes(method, fromDomain) }) {
+			return nil
+		}
+	}
+	requiredStr := strings.Join(required, "=pass or ") + "=pass"
+	if len(results) == 0 {
+		return fmt.Errorf("%w: %s required, got no results from %q", ErrUnauthenticated, requiredStr, authservID)
+	}
+	got := make([]string, len(results))
+	for i, r := range results {
+		got[i] = r.String()
+	}
+	return fmt.Errorf("%w: %s required, got results from %q: %s", ErrUnauthenticated, requiredStr, authservID, strings.Join(got, " "))
+}
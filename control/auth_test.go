@@ -0,0 +1,100 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package control
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestParseAuthResults(t *testing.T) {
+	cases := []struct {
+		name            string
+		value           string
+		expectedID      string
+		expectedResults []AuthResult
+	}{
+		{
+			name:       "none",
+			value:      "mx.club1.fr; none",
+			expectedID: "mx.club1.fr",
+		},
+		{
+			name: "multiple",
+			value: `mx.club1.fr 1;
+	spf=pass (sender SPF authorized) smtp.mailfrom=test@club1.fr;
+	dkim=pass (2048-bit key) header.d=club1.fr header.s=mail;
+	dmarc=fail header.from="club1.fr"`,
+			expectedID: "mx.club1.fr",
+			expectedResults: []AuthResult{
+				{Method: "spf", Result: "pass", Properties: map[string]string{"smtp.mailfrom": "test@club1.fr"}},
+				{Method: "dkim", Result: "pass", Properties: map[string]string{"header.d": "club1.fr", "header.s": "mail"}},
+				{Method: "dmarc", Result: "fail", Properties: map[string]string{"header.from": "club1.fr"}},
+			},
+		},
+		{
+			name:       "version and case",
+			value:      "MX.club1.fr; DKIM/1=Pass header.i=@Club1.fr",
+			expectedID: "MX.club1.fr",
+			expectedResults: []AuthResult{
+				{Method: "dkim", Result: "pass", Properties: map[string]string{"header.i": "@Club1.fr"}},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			id, results := ParseAuthResults(c.value)
+			if id != c.expectedID {
+				t.Errorf("expected authserv-id %q, got %q", c.expectedID, id)
+			}
+			if !reflect.DeepEqual(results, c.expectedResults) {
+				t.Errorf("expected results:\n%#v\ngot:\n%#v", c.expectedResults, results)
+			}
+		})
+	}
+}
+
+func TestAuthResultPasses(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   AuthResult
+		method   string
+		expected bool
+	}{
+		{"dmarc", AuthResult{Method: "dmarc", Result: "pass", Properties: map[string]string{"header.from": "Mail.club1.fr"}}, newsletter.AuthDMARC, true},
+		{"dmarc without from", AuthResult{Method: "dmarc", Result: "pass"}, newsletter.AuthDMARC, false},
+		{"dmarc other from", AuthResult{Method: "dmarc", Result: "pass", Properties: map[string]string{"header.from": "evil.fr"}}, newsletter.AuthDMARC, false},
+		{"dmarc fail", AuthResult{Method: "dmarc", Result: "fail"}, newsletter.AuthDMARC, false},
+		{"dkim parent domain", AuthResult{Method: "dkim", Result: "pass", Properties: map[string]string{"header.d": "fr"}}, newsletter.AuthDKIM, false},
+		{"dkim aligned", AuthResult{Method: "dkim", Result: "pass", Properties: map[string]string{"header.d": "club1.fr"}}, newsletter.AuthDKIM, true},
+		{"dkim other domain", AuthResult{Method: "dkim", Result: "pass", Properties: map[string]string{"header.d": "evil.fr"}}, newsletter.AuthDKIM, false},
+		{"spf aligned", AuthResult{Method: "spf", Result: "pass", Properties: map[string]string{"smtp.mailfrom": "bounce@club1.fr"}}, newsletter.AuthSPF, true},
+		{"wrong method", AuthResult{Method: "spf", Result: "pass", Properties: map[string]string{"smtp.mailfrom": "club1.fr"}}, newsletter.AuthDKIM, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if passes := c.result.passes(c.method, "mail.club1.fr"); passes != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, passes)
+			}
+		})
+	}
+}
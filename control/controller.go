@@ -20,20 +20,18 @@
 package control
 
 import (
-	"crypto/sha256"
-	"encoding/base32"
 	"errors"
 	"fmt"
 	"io"
 	"log/syslog"
-	"os"
-	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/club-1/newsletter-go/v3"
 	"github.com/club-1/newsletter-go/v3/mailer"
 	"github.com/club-1/newsletter-go/v3/messages"
+	"github.com/mnako/letters"
 )
 
 const (
@@ -43,34 +41,61 @@ const (
 type Controller struct {
 	log *Logger
 	nl  *newsletter.Newsletter
+	// entry is the journal entry of the request being handled.
+	entry *newsletter.JournalEntry
+	// worker starts a process that runs the queue, if not nil.
+	worker func() error
+	// responded reports whether a response has been sent to the request
+	// being handled.
+	responded bool
 }
 
 func NewController() (*Controller, error) {
+	return NewListController("")
+}
+
+// NewListController creates a [Controller] for the list with the given
+// name, or for the default list if name is empty.
+func NewListController(list string) (*Controller, error) {
+	return NewListControllerIn("", list)
+}
+
+// NewListControllerIn is like [NewListController], with the lists stored
+// in the given base config directory, see [newsletter.NewListIn].
+func NewListControllerIn(base string, list string) (*Controller, error) {
 	sysLog, err := syslog.New(syslog.LOG_USER, logIdentifier)
 	if err != nil {
 		return nil, fmt.Errorf("init syslog: %w", err)
 	}
 	logger := &Logger{Writer: sysLog}
 
-	nl, err := newsletter.New()
+	nl, err := newsletter.NewListIn(base, list)
 	if err != nil {
 		logger.Criticalf("init newsletter: %v", err)
 		return nil, err
 	}
-	messages.SetLanguage(nl.Config.Settings.Language)
+	messages.SetLanguage(nl.Config.LanguageOrDefault())
 
 	logger.AddContext(nl.LocalUser)
+	if nl.List != "" {
+		logger.AddContext(fmt.Sprintf("list %q", nl.List))
+	}
 
 	return &Controller{
-		log: logger,
-		nl:  nl,
+		log:    logger,
+		nl:     nl,
+		worker: func() error { return startWorker(base, list) },
 	}, nil
 }
 
 // response creates a new [mailer.Mail] directed towards the request's From
 // address.
 func (c *Controller) response(req *Request, subject string, body string) *mailer.Mail {
-	mail := c.nl.DefaultMail(subject, body)
+	return c.reply(req, c.nl.DefaultMail(subject, body))
+}
+
+// reply turns the given mail into a reply to the request.
+func (c *Controller) reply(req *Request, mail *mailer.Mail) *mailer.Mail {
 	mail.InReplyTo = fmt.Sprintf("<%s>", req.MessageID)
 	mail.To = req.From.Address
 
@@ -87,6 +112,7 @@ func (c *Controller) response(req *Request, subject string, body string) *mailer
 // sendResponse sends a reply to the received mail and logs the result.
 func (c *Controller) sendResponse(req *Request, subject string, body string) {
 	mail := c.response(req, subject, body)
+	c.responded = true
 	err := c.nl.Mailer.Send(mail)
 	if err != nil {
 		c.log.Errorf("error while sending response mail: %v", err)
@@ -95,58 +121,118 @@ func (c *Controller) sendResponse(req *Request, subject string, body string) {
 	}
 }
 
-func hashString(s string) string {
-	sum := sha256.Sum256([]byte(s))
-	return base32.StdEncoding.EncodeToString(sum[0:32])
-}
-
 func (c *Controller) HashWithSecret(s string) string {
-	return hashString(s + c.nl.Config.Secret)
+	return c.nl.HashWithSecret(s)
 }
 
 // GenerateId generates a Message-ID for this newsletter using the given hash.
 func (c *Controller) GenerateId(hash string) string {
-	return fmt.Sprintf("%s-%s@%s", c.nl.LocalUser, hash, c.nl.Hostname)
+	return c.nl.GenerateId(hash)
 }
 
-func (c *Controller) GenerateConfirmID(req *Request) string {
-	hash := c.HashWithSecret(req.From.Address)
-	return c.GenerateId(hash)
+func (c *Controller) GenerateConfirmID(req *Request, topics []string) string {
+	return c.nl.ConfirmID(req.From.Address, topics)
 }
 
-// GetHashFromId retrieves the hash from the given messageID of the form: `USER-HASH@SERVER`
+// GetHashFromId retrieves the hash from the given messageID of the form: `USER-[LIST-]HASH@SERVER`
 func (c *Controller) GetHashFromId(messageID string) (string, error) {
-	after, prefixFound := strings.CutPrefix(messageID, c.nl.LocalUser+"-")
-	before, suffixFound := strings.CutSuffix(after, "@"+c.nl.Hostname)
-	if !prefixFound || !suffixFound {
-		return "", errors.New("message ID doesn't match generated ID form")
+	return c.nl.GetHashFromId(messageID)
+}
+
+// alreadySubscribed reports whether the address of the request is already
+// subscribed to all the given topics, and replies accordingly if it is.
+func (c *Controller) alreadySubscribed(req *Request, topics []string) (bool, error) {
+	sub, err := c.nl.Config.Subscribers.Get(req.From.Address)
+	if errors.Is(err, newsletter.ErrNotSubscribed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check subscription: %w", err)
+	}
+	for _, topic := range topics {
+		if !sub.HasTopic(topic) {
+			return false, nil
+		}
 	}
-	return before, nil
+	c.log.Warningf("address is already subscribed: %s", req.From.Address)
+	c.sendResponse(
+		req,
+		messages.AlreadySubscribed_subject.Print(),
+		fmt.Sprintf(messages.AlreadySubscribed_body.Print(), c.nl.PostmasterAddr()),
+	)
+	return true, nil
 }
 
-func (c *Controller) subscribe(req *Request) error {
-	if slices.Contains(c.nl.Config.Emails, req.From.Address) {
-		c.log.Warningf("address is already subscribed: %s", req.From.Address)
-		c.sendResponse(
-			req,
-			messages.AlreadySubscribed_subject.Print(),
-			fmt.Sprintf(messages.AlreadySubscribed_body.Print(), c.nl.PostmasterAddr()),
-		)
-		return nil
+// reject logs that the request is rejected, and records it in the journal.
+func (c *Controller) reject(format string, v ...any) {
+	c.log.Warningf(format, v...)
+	c.entry.Outcome = newsletter.OutcomeRejected
+	c.entry.Detail = fmt.Sprintf(format, v...)
+}
+
+// topicsDetail returns the detail of the journal entry of a subscription
+// to the given topics.
+func topicsDetail(topics []string) string {
+	if len(topics) == 0 {
+		return ""
+	}
+	return "topics: " + strings.Join(topics, ", ")
+}
+
+// refused reports whether the address of the request is not allowed to
+// subscribe, because it is blocked or suppressed. The request is then only
+// logged, as replying to it could be backscatter.
+func (c *Controller) refused(req *Request) (bool, error) {
+	err := c.nl.Config.CheckAllowed(req.From.Address, true)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, newsletter.ErrBlocked), errors.Is(err, newsletter.ErrSuppressed):
+		c.reject("subscription refused: %v", err)
+		return true, nil
+	default:
+		return false, fmt.Errorf("check address: %w", err)
 	}
+}
 
-	var responseBody string
-	if c.nl.Config.Settings.Title == "" {
-		responseBody = fmt.Sprintf(messages.ConfirmSubscriptionAlt_body.Print(), c.nl.LocalUser)
+// subscribe sends a subscription confirmation mail. The topics are taken
+// from the route if it is a topic route, or from the subject and the first
+// line of the request otherwise.
+func (c *Controller) subscribe(req *Request, routeTopic string) error {
+	var topics []string
+	if routeTopic != "" {
+		topics = []string{routeTopic}
 	} else {
-		responseBody = fmt.Sprintf(messages.ConfirmSubscription_body.Print(), c.nl.Config.Settings.Title)
+		firstLine, _, _ := strings.Cut(strings.TrimSpace(req.Text), "\n")
+		topics = c.nl.FindTopics(req.Headers.Subject, firstLine)
 	}
 
-	mail := c.response(req, messages.ConfirmSubscription_subject.Print(), responseBody)
-	mail.ReplyTo = c.nl.SubscribeConfirmAddr()
-	mail.Id = fmt.Sprintf("<%s>", c.GenerateConfirmID(req))
+	if refused, err := c.refused(req); err != nil || refused {
+		return err
+	}
+	if c.nl.Config.Settings.SubscriptionPolicy == newsletter.SubscriptionInvite {
+		c.reject("subscription refused: the list is invite-only")
+		return nil
+	}
 
-	err := c.nl.Mailer.Send(mail)
+	// replies are throttled, as the request may be forged
+	err := c.nl.Throttle(req.From.Address)
+	if errors.Is(err, newsletter.ErrThrottled) {
+		c.reject("subscription request throttled: %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("throttle: %w", err)
+	}
+
+	subscribed, err := c.alreadySubscribed(req, topics)
+	if err != nil || subscribed {
+		return err
+	}
+
+	c.entry.Detail = topicsDetail(topics)
+	mail := c.reply(req, c.nl.ConfirmSubscriptionMail(req.From.Address, topics))
+	err = c.nl.Mailer.Send(mail)
 	if err != nil {
 		return fmt.Errorf("send response mail: %v", err)
 	}
@@ -156,42 +242,55 @@ func (c *Controller) subscribe(req *Request) error {
 }
 
 func (c *Controller) subscribeConfirm(req *Request) error {
-	if slices.Contains(c.nl.Config.Emails, req.From.Address) {
-		c.log.Warningf("address is already subscribed: %s", req.From.Address)
-		c.sendResponse(
-			req,
-			messages.AlreadySubscribed_subject.Print(),
-			fmt.Sprintf(messages.AlreadySubscribed_body.Print(), c.nl.PostmasterAddr()),
-		)
-		return nil
-	}
-
 	if len(req.Headers.InReplyTo) == 0 {
 		return fmt.Errorf("missing In-Reply-To header")
 	}
 
 	messageId := string(req.Headers.InReplyTo[0])
-	if messageId != c.GenerateConfirmID(req) {
+	topics, verifyErr := c.nl.VerifyConfirmID(messageId, req.From.Address)
+
+	subscribed, err := c.alreadySubscribed(req, topics)
+	if err != nil || subscribed {
+		return err
+	}
+
+	if errors.Is(verifyErr, newsletter.ErrExpiredToken) {
+		c.sendResponse(
+			req,
+			messages.ConfirmationExpired_subject.Print(),
+			fmt.Sprintf(messages.ConfirmationExpired_body.Print(), c.nl.SubscribeAddr()),
+		)
+		return fmt.Errorf("hash verification failed: %w", verifyErr)
+	}
+	if verifyErr != nil {
 		c.sendResponse(
 			req,
 			messages.VerificationFailed_subject.Print(),
 			fmt.Sprintf(messages.VerificationFailed_body.Print(), c.nl.LocalUserAddr()),
 		)
-		return fmt.Errorf("hash verification failed")
+		return fmt.Errorf("hash verification failed: %w", verifyErr)
+	}
+
+	if refused, err := c.refused(req); err != nil || refused {
+		return err
 	}
 
-	err := c.nl.Config.Subscribe(req.From.Address)
+	if c.nl.Config.Settings.SubscriptionPolicy == newsletter.SubscriptionApprove {
+		return c.requestApproval(req, topics)
+	}
+
+	err = c.nl.Config.SubscribeTopics(req.From.Address, topics)
 	if err != nil {
 		return fmt.Errorf("error while subscribing address: %v", err)
 	}
+	if err := c.nl.Config.Unsuppress(req.From.Address); err != nil {
+		c.log.Warningf("could not remove address from suppression list: %v", err)
+	}
 	c.log.Infof("address %q has been added to subscribers", req.From.Address)
+	c.entry.Detail = topicsDetail(topics)
 
-	var responseBody string
-	if c.nl.Config.Settings.Title == "" {
-		responseBody = fmt.Sprintf(messages.SuccessfullSubscriptionAlt_body.Print(), c.nl.LocalUser)
-	} else {
-		responseBody = fmt.Sprintf(messages.SuccessfullSubscription_body.Print(), c.nl.Config.Settings.Title)
-	}
+	responseBody := c.nl.TitledBody(messages.SuccessfullSubscription_body, messages.SuccessfullSubscriptionAlt_body)
+	responseBody += newsletter.TopicsLine(topics)
 
 	c.sendResponse(req, messages.SuccessfullSubscription_subject.Print(), responseBody)
 	return nil
@@ -205,62 +304,157 @@ func (c *Controller) unsubscribe(req *Request) error {
 	case errors.Is(err, newsletter.ErrNotSubscribed):
 		c.log.Warningf("address is not subscribed: %s", req.From.Address)
 	default:
-		var responseBody string
-		if c.nl.Config.Settings.Title == "" {
-			responseBody = fmt.Sprintf(messages.UnsubscriptionFailedAlt_body.Print(), c.nl.LocalUser, c.nl.LocalUserAddr())
-		} else {
-			responseBody = fmt.Sprintf(messages.UnsubscriptionFailed_body.Print(), c.nl.Config.Settings.Title, c.nl.LocalUserAddr())
-		}
+		responseBody := c.nl.TitledBody(messages.UnsubscriptionFailed_body, messages.UnsubscriptionFailedAlt_body, c.nl.LocalUserAddr())
 		c.sendResponse(req, messages.UnsubscriptionFailed_subject.Print(), responseBody)
 		return fmt.Errorf("could not unsubscribe: %w", err)
 	}
 
-	var responseBody string
-	if c.nl.Config.Settings.Title == "" {
-		responseBody = fmt.Sprintf(messages.SuccessfullUnsubscriptionAlt_body.Print(), c.nl.LocalUser)
-	} else {
-		responseBody = fmt.Sprintf(messages.SuccessfullUnsubscription_body.Print(), c.nl.Config.Settings.Title)
-	}
-
+	responseBody := c.nl.TitledBody(messages.SuccessfullUnsubscription_body, messages.SuccessfullUnsubscriptionAlt_body)
 	c.sendResponse(req, messages.SuccessfullUnsubscription_subject.Print(), responseBody)
 	return nil
 }
 
+// previewMail creates the preview of the issue with the given hash, whose
+// confirmation is expected from the given editor, with the given note
+// appended to the body. The submitted message is used for the HTML body and
+// the files of the issue, if not nil.
+func (c *Controller) previewMail(subject string, body string, email *letters.Email, hash string, editor string, note string) *mailer.Mail {
+	mail := c.nl.DefaultMail(subject, body)
+	mail.Id = c.nl.SendID(editor, hash)
+	trailer := c.nl.Footer("") + "\n\n" + note
+	mail.Body += trailer
+	mail.ReplyTo = c.nl.SendConfirmAddr()
+	c.addRichContent(mail, email, trailer)
+	return mail
+}
+
 func (c *Controller) send(req *Request) error {
-	if req.From.Address != c.nl.LocalUserAddr() {
-		return fmt.Errorf("email From doesn't match user address")
+	if !c.nl.IsEditor(req.From.Address) {
+		return fmt.Errorf("email From doesn't match user or editor address")
 	}
 
-	body := req.Text
-	subject := req.Headers.Subject
+	body := bodyText(&req.Email)
+	subject, at, scheduled := newsletter.CutSendAt(req.Headers.Subject)
+	var sendAt time.Time
+	if scheduled {
+		var err error
+		if sendAt, err = c.nl.ParseSendAt(at); err != nil {
+			return err
+		}
+	}
 
-	hash := c.HashWithSecret(body + subject)
+	attachments := issueAttachments(&req.Email)
+	if err := c.checkAttachments(attachments); err != nil {
+		return err
+	}
+	// the submitted message is only kept if it has more than a text body
+	var email *letters.Email
+	content := body + subject
+	if req.HTML != "" || len(attachments) > 0 {
+		email = &req.Email
+		content += "\x00" + req.HTML
+		for _, a := range attachments {
+			content += "\x00" + a.Filename + "\x00" + string(a.Data)
+		}
+	}
 
-	bodyFilePath := filepath.Join(os.TempDir(), "newsletter-send-"+hash+".body.txt")
-	subjectFilePath := filepath.Join(os.TempDir(), "newsletter-send-"+hash+".subject.txt")
+	hash := c.HashWithSecret(content)
 
-	var err error
-	err = os.WriteFile(bodyFilePath, []byte(body), 0660)
-	if err != nil {
+	if count, err := c.nl.CleanIssues(); err != nil {
+		c.log.Warningf("clean expired issues: %v", err)
+	} else if count > 0 {
+		c.log.Infof("removed %v expired issues", count)
+	}
+	var message []byte
+	if email != nil {
+		message = req.Raw
+	}
+	issue := &newsletter.Issue{ID: hash, Subject: subject, Submitter: req.From.Address, SendAt: sendAt}
+	if err := c.nl.CreateIssue(issue, body, message); err != nil {
 		return err
 	}
-	err = os.WriteFile(subjectFilePath, []byte(subject), 0660)
+
+	count, err := c.nl.Config.Subscribers.Count()
 	if err != nil {
-		return err
+		return fmt.Errorf("count subscribers: %w", err)
 	}
 
-	mail := c.nl.DefaultMail(subject, body)
-	mail.Id = c.GenerateId(hash)
-	mail.Body += fmt.Sprintf(messages.Newsletter_footer.Print(), c.nl.UnsubscribeAddr())
-	mail.Body += fmt.Sprintf("\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the %v subscribers, reply to this email)", len(c.nl.Config.Emails))
-	mail.ReplyTo = c.nl.SendConfirmAddr()
+	note := fmt.Sprintf("(this is a preview mail, if you want to confirm and send the newsletter to all the %v subscribers%s, reply to this email with a line containing only %s, or %s to cancel it)", count, c.sendAtNote(issue), c.sendKeyword(), c.cancelKeyword())
+	mail := c.previewMail(subject, body, email, hash, req.From.Address, note)
+	return c.nl.SendPreviewMailTo(*mail, req.From.Address)
+}
+
+// requestSendApprovals sends the preview of the issue with the given hash
+// to the editors that did not approve it yet.
+func (c *Controller) requestSendApprovals(issue *newsletter.Issue, body string, email *letters.Email, approvals []string) error {
+	subject, hash := issue.Subject, issue.ID
+	count, err := c.nl.Config.Subscribers.Count()
+	if err != nil {
+		return fmt.Errorf("count subscribers: %w", err)
+	}
+	note := fmt.Sprintf("(this is a preview mail confirmed by %s, if you want to approve sending the newsletter to all the %v subscribers%s, reply to this email with a line containing only %s, or %s to cancel it)", strings.Join(approvals, ", "), count, c.sendAtNote(issue), c.sendKeyword(), c.cancelKeyword())
+	var errs []error
+	for _, editor := range c.nl.Editors() {
+		if slices.ContainsFunc(approvals, func(a string) bool { return strings.EqualFold(a, editor) }) {
+			continue
+		}
+		mail := c.previewMail(subject, body, email, hash, editor, note)
+		errs = append(errs, c.nl.SendPreviewMailTo(*mail, editor))
+	}
+	return errors.Join(errs...)
+}
+
+// sendAtNote returns the part of the preview notes that tells when the
+// given issue is scheduled, if it is.
+func (c *Controller) sendAtNote(issue *newsletter.Issue) string {
+	if issue.SendAt.IsZero() {
+		return ""
+	}
+	return " on " + c.nl.FormatSendAt(issue.SendAt)
+}
+
+func (c *Controller) sendKeyword() string {
+	return c.nl.Config.Settings.SendKeywordOrDefault()
+}
 
-	return c.nl.SendPreviewMail(*mail)
+func (c *Controller) cancelKeyword() string {
+	return c.nl.Config.Settings.CancelKeywordOrDefault()
+}
+
+// alreadySent rejects the confirmation of an issue that has already been
+// sent, or is being sent, and notifies the owner.
+func (c *Controller) alreadySent(req *Request, issue *newsletter.Issue) {
+	c.reject("newsletter already %s: %s", issue.State, issue.ID)
+	c.notifyOwner(req, messages.IssueAlreadySent_subject.Print(), fmt.Sprintf(messages.IssueAlreadySent_body.Print(), issue.Subject, req.From.Address))
+}
+
+// notifyOwner sends a mail to the owner about the request, in reply to it.
+func (c *Controller) notifyOwner(req *Request, subject string, body string) {
+	mail := c.response(req, subject, body)
+	mail.To = c.nl.LocalUserAddr()
+	c.responded = true
+	if err := c.nl.Mailer.Send(mail); err != nil {
+		c.log.Errorf("error while sending notice mail: %v", err)
+	}
+}
+
+// notifyRejected notifies the owner that the request to the given route
+// has been rejected, unless a response has already been sent.
+func (c *Controller) notifyRejected(req *Request, route string) {
+	if c.responded {
+		return
+	}
+	// the notices are throttled, as the request may be forged
+	if err := c.nl.ThrottleNotice(req.From.Address); err != nil {
+		c.log.Warningf("rejection notice not sent: %v", err)
+		return
+	}
+	c.notifyOwner(req, messages.RequestRejected_subject.Print(), fmt.Sprintf(messages.RequestRejected_body.Print(), req.From.Address, route, c.entry.Detail))
 }
 
 func (c *Controller) sendConfirm(req *Request) error {
-	if req.From.Address != c.nl.LocalUserAddr() {
-		return fmt.Errorf("email From header doesn't match user address")
+	if !c.nl.IsEditor(req.From.Address) {
+		return fmt.Errorf("email From header doesn't match user or editor address")
 	}
 
 	if len(req.Headers.InReplyTo) == 0 {
@@ -268,41 +462,111 @@ func (c *Controller) sendConfirm(req *Request) error {
 	}
 
 	messageId := string(req.Headers.InReplyTo[0])
-	hash, err := c.GetHashFromId(messageId)
+	hash, err := c.nl.VerifySendID(messageId, req.From.Address)
 	if err != nil {
-		return fmt.Errorf("In-Reply-To parsing error: %w", err)
+		return fmt.Errorf("In-Reply-To verification error: %w", err)
 	}
 
-	bodyFilePath := filepath.Join(os.TempDir(), "newsletter-send-"+hash+".body.txt")
-	subjectFilePath := filepath.Join(os.TempDir(), "newsletter-send-"+hash+".subject.txt")
-
-	var body string
-	bodyB, err := os.ReadFile(bodyFilePath)
+	issue, err := c.nl.Issue(hash)
 	if err != nil {
-		return fmt.Errorf("read temporary body file: %w", err)
+		return err
 	}
-	body = string(bodyB)
-
-	var subject string
-	subjectB, err := os.ReadFile(subjectFilePath)
+	subject := issue.Subject
+	body, err := c.nl.IssueBody(issue)
 	if err != nil {
-		return fmt.Errorf("read temporary subject file: %w", err)
+		return err
+	}
+	if issue.State != newsletter.IssuePending {
+		c.alreadySent(req, issue)
+		return nil
+	}
+	switch c.nl.ConfirmAction(bodyText(&req.Email), newsletter.ActionSend, newsletter.ActionCancel) {
+	case newsletter.ActionCancel:
+		return c.cancelIssue(req, issue)
+	case "":
+		c.reject("no %s or %s keyword in the confirmation", c.sendKeyword(), c.cancelKeyword())
+		c.sendResponse(req, messages.IssueNoKeyword_subject.Print(), fmt.Sprintf(messages.IssueNoKeyword_body.Print(), c.sendKeyword(), c.cancelKeyword(), subject))
+		return nil
+	}
+	email, err := c.issueMessage(issue)
+	if err != nil {
+		return err
+	}
+
+	if required := c.nl.Config.Settings.SendApprovalsOrDefault(); required > 1 {
+		approvals, err := c.nl.ApproveIssue(issue, req.From.Address)
+		if err != nil {
+			return err
+		}
+		if len(approvals) < required {
+			c.entry.Detail = fmt.Sprintf("approved by %s (%v/%v)", req.From.Address, len(approvals), required)
+			c.log.Infof("newsletter approved by %v/%v editors", len(approvals), required)
+			c.sendResponse(req, messages.IssueApproved_subject.Print(), fmt.Sprintf(messages.IssueApproved_body.Print(), subject, required-len(approvals)))
+			if len(approvals) == 1 {
+				return c.requestSendApprovals(issue, body, email, approvals)
+			}
+			return nil
+		}
 	}
-	subject = string(subjectB)
 
 	mail := c.nl.DefaultMail(subject, body)
-	mail.Body += fmt.Sprintf(messages.Newsletter_footer.Print(), c.nl.UnsubscribeAddr())
-	errs := slices.Collect(c.nl.SendNews(mail))
-	err = errors.Join(errs...)
+	mail.Body += c.nl.Footer("")
+	c.addRichContent(mail, email, c.nl.Footer(""))
+	for _, id := range req.Headers.References {
+		issue.Thread = append(issue.Thread, string(id))
+	}
+	if len(issue.Thread) == 0 {
+		issue.Thread = []string{messageId}
+	}
+	issue.Thread = append(issue.Thread, req.MessageID)
+	if err := c.nl.QueueIssue(issue, mail); errors.Is(err, newsletter.ErrIssueSent) {
+		c.alreadySent(req, issue)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	count, err := c.nl.Config.Subscribers.Count()
 	if err != nil {
-		return fmt.Errorf("sending newsletter: %w", err)
+		return fmt.Errorf("count subscribers: %w", err)
+	}
+	if issue.Scheduled(c.now()) {
+		at := c.nl.FormatSendAt(issue.SendAt)
+		c.entry.Detail = fmt.Sprintf("scheduled for %v subscribers on %s", count, at)
+		c.log.Infof("newsletter scheduled for %v subscribers on %s", count, at)
+		c.sendResponse(req, messages.IssueQueued_subject.Print(), fmt.Sprintf(messages.IssueScheduled_body.Print(), subject, count, at))
+		return nil
+	}
+	c.entry.Detail = fmt.Sprintf("queued for %v subscribers", count)
+	c.log.Infof("newsletter queued for %v subscribers", count)
+	c.sendResponse(req, messages.IssueQueued_subject.Print(), fmt.Sprintf(messages.IssueQueued_body.Print(), subject, count))
+	if c.worker != nil {
+		if err := c.worker(); err != nil {
+			c.log.Errorf("start queue worker: %v", err)
+		}
+	}
+	return nil
+}
+
+// cancelIssue discards the given issue, as requested by the reply to its
+// preview.
+func (c *Controller) cancelIssue(req *Request, issue *newsletter.Issue) error {
+	if err := c.nl.DiscardIssue(issue.ID); err != nil {
+		return err
 	}
-	c.log.Infof("newsletter successfully sent to all the %v subscribers", len(c.nl.Config.Emails))
+	c.entry.Detail = "cancelled"
+	c.log.Infof("newsletter cancelled: %s", issue.ID)
+	c.sendResponse(req, messages.IssueCancelled_subject.Print(), fmt.Sprintf(messages.IssueCancelled_body.Print(), issue.Subject))
 	return nil
 }
 
 func (c *Controller) Handle(route string, r io.Reader) error {
-	c.log.AddContext(fmt.Sprintf("route %q", route))
+	// the secret send token must not be written in the logs
+	isSendTokenRoute := c.nl.IsSendTokenRoute(route)
+	loggedRoute := route
+	if isSendTokenRoute {
+		loggedRoute = newsletter.RouteSend + "-TOKEN"
+	}
+	c.log.AddContext(fmt.Sprintf("route %q", loggedRoute))
 
 	request, err := ParseRequest(r)
 	if err != nil {
@@ -314,24 +578,78 @@ func (c *Controller) Handle(route string, r io.Reader) error {
 
 	var cmdErr error
 
-	switch route {
-	case newsletter.RouteSubscribe:
-		cmdErr = c.subscribe(request)
-	case newsletter.RouteSubscribeConfirm:
+	topic, isTopicRoute := strings.CutPrefix(route, newsletter.RouteSubscribe+"-")
+	isTopicRoute = isTopicRoute && slices.Contains(c.nl.Config.Settings.Topics, topic)
+
+	event := route
+	switch {
+	case isTopicRoute:
+		event = newsletter.RouteSubscribe
+	case isSendTokenRoute:
+		event = newsletter.RouteSend
+	}
+	c.entry = &newsletter.JournalEntry{
+		Event:     event,
+		Route:     loggedRoute,
+		Address:   request.From.Address,
+		MessageID: request.MessageID,
+		Outcome:   newsletter.OutcomeOK,
+	}
+	defer c.record()
+
+	// the owner is notified of the rejected news and confirmations
+	isSendEvent := event == newsletter.RouteSend || event == newsletter.RouteSendConfirm
+
+	if err := c.checkAuth(event, request); err != nil {
+		c.log.Errorf("request rejected: %v", err)
+		c.entry.Outcome = newsletter.OutcomeRejected
+		c.entry.Detail = err.Error()
+		if isSendEvent {
+			c.notifyRejected(request, loggedRoute)
+		}
+		return err
+	}
+
+	switch {
+	case isTopicRoute:
+		cmdErr = c.subscribe(request, topic)
+	case route == newsletter.RouteSubscribe:
+		cmdErr = c.subscribe(request, "")
+	case route == newsletter.RouteSubscribeConfirm:
 		cmdErr = c.subscribeConfirm(request)
-	case newsletter.RouteUnSubscribe:
+	case route == newsletter.RouteUnSubscribe:
 		cmdErr = c.unsubscribe(request)
-	case newsletter.RouteSend:
+	case isSendTokenRoute:
+		cmdErr = c.send(request)
+	case route == newsletter.RouteSend && c.nl.Config.Settings.RequireSendToken:
+		c.reject("news refused: it must be sent to the secret send address")
+	case route == newsletter.RouteSend:
 		cmdErr = c.send(request)
-	case newsletter.RouteSendConfirm:
+	case route == newsletter.RouteSendConfirm:
 		cmdErr = c.sendConfirm(request)
+	case route == newsletter.RouteAdmin:
+		cmdErr = c.admin(request)
+	case route == newsletter.RouteModerate:
+		cmdErr = c.moderate(request)
 	default:
 		c.log.Errorf("invalid sub command: %q", route)
 	}
 
 	if cmdErr != nil {
 		c.log.Errorf("error: %v", cmdErr)
+		c.entry.Outcome = newsletter.OutcomeError
+		c.entry.Detail = cmdErr.Error()
+	}
+	if isSendEvent && c.entry.Outcome != newsletter.OutcomeOK {
+		c.notifyRejected(request, loggedRoute)
 	}
 
 	return cmdErr
 }
+
+// record appends the entry of the handled request to the journal.
+func (c *Controller) record() {
+	if err := c.nl.Record(*c.entry); err != nil {
+		c.log.Errorf("record journal entry: %v", err)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/club-1/newsletter-go/v3"
 	"github.com/club-1/newsletter-go/v3/mailer"
@@ -35,22 +36,34 @@ import (
 
 func fakeNewsletter(t *testing.T) *newsletter.Newsletter {
 	t.Helper()
+	dir := t.TempDir()
+	subscribers, err := newsletter.OpenFileStore(filepath.Join(dir, newsletter.EmailsFile))
+	if err != nil {
+		t.Fatalf("open subscribers: %v", err)
+	}
+	if err := subscribers.Add(&newsletter.Subscriber{Address: "recipient@club1.fr"}); err != nil {
+		t.Fatalf("add subscriber: %v", err)
+	}
 	return &newsletter.Newsletter{
 		Config: &newsletter.Config{
-			Dir: t.TempDir(),
-			Emails: []string{
-				"recipient@club1.fr",
-			},
-			Secret: "BASIC_SECRET",
+			Dir:         dir,
+			Subscribers: subscribers,
+			Secret:      "BASIC_SECRET",
 			Settings: newsletter.Settings{
 				Title:       "Title",
 				DisplayName: "Display Name",
 				Language:    messages.LangEnglish,
+				Topics:      []string{"events", "digest"},
+				// legacy IDs are still accepted at the fake current time
+				LegacyTokensUntil: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC),
 			},
 			Signature: "Bye bye",
 		},
 		Hostname:  "club1.fr",
 		LocalUser: "user",
+		Now: func() time.Time {
+			return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		},
 	}
 }
 
@@ -63,8 +76,13 @@ func setupTest(t *testing.T) (*Controller, *DummySyslog) {
 	return &Controller{log: logger, nl: nl}, syslog
 }
 
-func handle(t *testing.T, route, stdin string) (*Controller, *DummySyslog, []mailer.Mail, error) {
+func handle(t *testing.T, route, stdin string, config func(c *newsletter.Config) error, runQueue bool) (*Controller, *DummySyslog, []mailer.Mail, error) {
 	controller, syslog := setupTest(t)
+	if config != nil {
+		if err := config(controller.nl.Config); err != nil {
+			t.Fatalf("setup config: %v", err)
+		}
+	}
 
 	var mails []mailer.Mail
 	controller.nl.Mailer = &mailertest.Mailer{Handler: func(m *mailer.Mail) error {
@@ -73,19 +91,50 @@ func handle(t *testing.T, route, stdin string) (*Controller, *DummySyslog, []mai
 	}}
 
 	err := controller.Handle(route, strings.NewReader(stdin))
+	if err == nil && runQueue {
+		err = controller.RunQueue()
+	}
 	return controller, syslog, mails, err
 }
 
 type testCase struct {
 	name  string
 	stdin string
-	// tmp allows to create files before the test and remove them once
-	// finished. The key is the path relative to [os.TempDir]. If the
-	// content is empty, the file will not be created, and only cleaned.
-	tmp           map[string]string
+	// issue is a pending issue to create before the test.
+	issue *testIssue
+	// runQueue runs the queue after the request is handled.
+	runQueue bool
+	// config allows to modify the configuration before the test.
+	config        func(c *newsletter.Config) error
 	expectedAddrs []string
 	expectedMails []mailer.Mail
+	expectedErr   string
 	expectedLog   string
+	// expectedJournal is compared to the journal if it is not nil.
+	expectedJournal []newsletter.JournalEntry
+	// expectedIssues is compared to the stored issues if it is not nil.
+	expectedIssues []newsletter.Issue
+}
+
+type testIssue struct {
+	id        string
+	subject   string
+	body      string
+	message   string
+	approvals []string
+	state     string
+	sendAt    time.Time
+}
+
+// adminCommandsConfig stores the commands of the admin/basic case, as
+// awaiting confirmation.
+func adminCommandsConfig(c *newsletter.Config) error {
+	dir := filepath.Join(c.Dir, newsletter.AdminDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	lines := "add test@club1.fr\nRemove recipient@club1.fr\nlist\ncount\nsettings title New title\n"
+	return os.WriteFile(filepath.Join(dir, "TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA===="), []byte(lines), 0600)
 }
 
 func TestHandle(t *testing.T) {
@@ -100,7 +149,31 @@ Subject: Subscribe
 			expectedMails: []mailer.Mail{{
 				From:            "Display Name <user@club1.fr>",
 				To:              "test@club1.fr",
-				Id:              "<user-NRGABAKKE6AKVXM5S7IJQOUFFOXC2B3UF5QWX5VYFAKBRNWHZBHQ====@club1.fr>",
+				Id:              "<user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ReplyTo:         "user+subscribe-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Please confirm your subsciption",
+				Body:            "Reply to this email to confirm that you want to subscribe to the newsletter [Title] (the content does not matter).\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe/after rejection notice",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				entry := "2026-10-01T11:30:00Z\ttest@club1.fr\n"
+				return os.WriteFile(filepath.Join(c.Dir, newsletter.NoticeThrottleFile), []byte(entry), 0660)
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				Id:              "<user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>",
 				InReplyTo:       "<fakeid@club1.fr>",
 				References:      "<fakeid@club1.fr>",
 				ReplyTo:         "user+subscribe-confirm@club1.fr",
@@ -129,11 +202,250 @@ Subject: Subscribe
 				Body:            "Your email is already subscribed, if problem persist, contact <postmaster@club1.fr>.\n\n-- \nBye bye",
 			}},
 		},
+		{
+			name: "subscribe/removed",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				return c.Suppress("test@club1.fr", newsletter.SuppressRemoved)
+			},
+			expectedLog: `subscription refused: address is suppressed: removed`,
+		},
+		{
+			name: "subscribe/blocked",
+			stdin: `From: test@spam.club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.BlockedDomains = []string{"spam.club1.fr"}
+				return nil
+			},
+			expectedLog: `subscription refused: address is blocked`,
+		},
+		{
+			name: "subscribe/throttled",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				entry := "2026-10-01T11:30:00Z\ttest@club1.fr\n"
+				return os.WriteFile(filepath.Join(c.Dir, newsletter.ThrottleFile), []byte(entry), 0660)
+			},
+			expectedLog: `subscription request throttled: too many confirmation mails: address in cooldown for 1h0m0s`,
+			expectedJournal: []newsletter.JournalEntry{{
+				Time:      time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				Event:     "subscribe",
+				Route:     "subscribe",
+				Address:   "test@club1.fr",
+				MessageID: "fakeid@club1.fr",
+				Outcome:   "rejected",
+				Detail:    "subscription request throttled: too many confirmation mails: address in cooldown for 1h0m0s",
+			}},
+		},
+		{
+			name: "subscribe/authenticated",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Authentication-Results: mx.club1.fr; dkim=pass header.d=club1.fr
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.AuthServID = "mx.club1.fr"
+				c.Settings.RequireAuth = map[string][]string{"subscribe": {"dmarc", "dkim"}}
+				return nil
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				Id:              "<user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ReplyTo:         "user+subscribe-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Please confirm your subsciption",
+				Body:            "Reply to this email to confirm that you want to subscribe to the newsletter [Title] (the content does not matter).\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe/untrusted authentication",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Authentication-Results: mx.club1.fr; dkim=fail header.d=club1.fr
+Authentication-Results: mx.evil.fr; dkim=pass header.d=club1.fr
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.AuthServID = "mx.club1.fr"
+				c.Settings.RequireAuth = map[string][]string{"subscribe": {"dkim"}}
+				return nil
+			},
+			expectedErr: "unauthenticated request",
+			expectedLog: `request rejected: unauthenticated request: dkim=pass required, got results from "mx.club1.fr": dkim=fail`,
+		},
+		{
+			name: "subscribe/unsubscribed",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				return c.Suppress("test@club1.fr", newsletter.SuppressUnsubscribed)
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				Id:              "<user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ReplyTo:         "user+subscribe-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Please confirm your subsciption",
+				Body:            "Reply to this email to confirm that you want to subscribe to the newsletter [Title] (the content does not matter).\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe-events/basic",
+			stdin: `From: test@club1.fr
+To: user+subscribe-events@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				Id:              "<user-2.sub.tm89c0.LUBQM3CHQA2JTR2QZD4G6USLOX3OGEE5CIQ2INMMGTQY2S7PLJXQ.events@club1.fr>",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ReplyTo:         "user+subscribe-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Please confirm your subsciption",
+				Body:            "Reply to this email to confirm that you want to subscribe to the newsletter [Title] (the content does not matter).\n\nTopics: events\n\n-- \nBye bye",
+			}},
+			expectedJournal: []newsletter.JournalEntry{{
+				Time:      time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				Event:     "subscribe",
+				Route:     "subscribe-events",
+				Address:   "test@club1.fr",
+				MessageID: "fakeid@club1.fr",
+				Outcome:   "ok",
+				Detail:    "topics: events",
+			}},
+		},
+		{
+			name: "subscribe/topic in subject",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe to Events please
+`,
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				Id:              "<user-2.sub.tm89c0.LUBQM3CHQA2JTR2QZD4G6USLOX3OGEE5CIQ2INMMGTQY2S7PLJXQ.events@club1.fr>",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ReplyTo:         "user+subscribe-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Please confirm your subsciption",
+				Body:            "Reply to this email to confirm that you want to subscribe to the newsletter [Title] (the content does not matter).\n\nTopics: events\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe-confirm/topic",
+			stdin: `From: test@club1.fr
+To: user+subscribe-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.sub.tm89c0.LUBQM3CHQA2JTR2QZD4G6USLOX3OGEE5CIQ2INMMGTQY2S7PLJXQ.events@club1.fr>
+Subject: Subscribe confirm
+`,
+			expectedAddrs: []string{"recipient@club1.fr", "test@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Subscription is successfull !",
+				Body:            "Your email has been successfully subscribed to the newsletter [Title].\n\nTopics: events\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe-confirm/forged topic",
+			stdin: `From: test@club1.fr
+To: user+subscribe-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.sub.tm89c0.LUBQM3CHQA2JTR2QZD4G6USLOX3OGEE5CIQ2INMMGTQY2S7PLJXQ.digest@club1.fr>
+Subject: Subscribe confirm
+`,
+			expectedErr:   "hash verification failed",
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Verification failed",
+				Body:            "Your email cannot be added to the subscripted list, contact list owner for more info: <user@club1.fr>.\n\n-- \nBye bye",
+			}},
+		},
 		{
 			name: "subscribe-confirm/basic",
 			stdin: `From: test@club1.fr
 To: user+subscribe-confirm@club1.fr
 Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>
+References: <user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>
+Subject: Subscribe confirm
+`,
+			expectedAddrs: []string{"recipient@club1.fr", "test@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Subscription is successfull !",
+				Body:            "Your email has been successfully subscribed to the newsletter [Title].\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe-confirm/removed",
+			stdin: `From: test@club1.fr
+To: user+subscribe-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>
+References: <user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>
+Subject: Subscribe confirm
+`,
+			config: func(c *newsletter.Config) error {
+				return c.Suppress("test@club1.fr", newsletter.SuppressRemoved)
+			},
+			expectedLog:   `subscription refused: address is suppressed: removed`,
+			expectedAddrs: []string{"recipient@club1.fr"},
+		},
+		{
+			name: "subscribe-confirm/legacy",
+			stdin: `From: test@club1.fr
+To: user+subscribe-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
 In-Reply-To: <user-NRGABAKKE6AKVXM5S7IJQOUFFOXC2B3UF5QWX5VYFAKBRNWHZBHQ====@club1.fr>
 References: <user-NRGABAKKE6AKVXM5S7IJQOUFFOXC2B3UF5QWX5VYFAKBRNWHZBHQ====@club1.fr>
 Subject: Subscribe confirm
@@ -150,6 +462,28 @@ Subject: Subscribe confirm
 				Body:            "Your email has been successfully subscribed to the newsletter [Title].\n\n-- \nBye bye",
 			}},
 		},
+		{
+			name: "subscribe-confirm/expired",
+			stdin: `From: test@club1.fr
+To: user+subscribe-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.sub.tlnw00.MUBZ4SAWVPIMD6OHBHSBSOEJBOUJ4Y6XUIMRFNTT4Q365JNOZQGQ@club1.fr>
+References: <user-2.sub.tlnw00.MUBZ4SAWVPIMD6OHBHSBSOEJBOUJ4Y6XUIMRFNTT4Q365JNOZQGQ@club1.fr>
+Subject: Subscribe confirm
+`,
+			expectedErr:   "expired token",
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<user-2.sub.tlnw00.MUBZ4SAWVPIMD6OHBHSBSOEJBOUJ4Y6XUIMRFNTT4Q365JNOZQGQ@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Confirmation expired",
+				Body:            "Your subscription request has expired, send a new mail to <user+subscribe@club1.fr> to subscribe again.\n\n-- \nBye bye",
+			}},
+		},
 		{
 			name: "unsubscribe/basic",
 			stdin: `From: recipient@club1.fr
@@ -170,7 +504,7 @@ Subject: Unsubscribe
 			}},
 		},
 		{
-			name: "send/basic",
+			name: "send/unauthenticated",
 			stdin: `From: user@club1.fr
 To: user+send@club1.fr
 Message-Id: <fakeid@club1.fr>
@@ -178,73 +512,1214 @@ Subject: Send
 
 Content of the mail!
 `,
-			tmp: map[string]string{
-				"newsletter-send-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====.subject.txt": "",
-				"newsletter-send-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====.body.txt":    "",
+			config: func(c *newsletter.Config) error {
+				c.Settings.AuthServID = "mx.club1.fr"
+				c.Settings.RequireAuth = map[string][]string{"send": {"dmarc"}}
+				return nil
 			},
+			expectedErr: "unauthenticated request",
+			expectedLog: `request rejected: unauthenticated request: dmarc=pass required, got no results from "mx.club1.fr"`,
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send address has been rejected:\nunauthenticated request: dmarc=pass required, got no results from \"mx.club1.fr\"\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send/basic",
+			stdin: `From: user@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			expectedIssues: []newsletter.Issue{{
+				ID:        "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				Subject:   "Send",
+				Submitter: "user@club1.fr",
+				CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC),
+			}},
 			expectedMails: []mailer.Mail{{
 				From:            "Display Name <user@club1.fr>",
 				To:              "user@club1.fr",
-				Id:              "user-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
+				Id:              "user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
 				InReplyTo:       "", // FIXME: shouldn't it be in reply to our message ID?
 				References:      "", // FIXME: shouldn't it be in our message's thread?
 				ReplyTo:         "user+send-confirm@club1.fr",
 				ListId:          "Display Name <user.club1.fr>",
 				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
 				Subject:         "[Title] Send (preview)",
-				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers, reply to this email)",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers, reply to this email with a line containing only SEND, or CANCEL to cancel it)",
 			}},
 		},
 		{
-			name: "send-confirm/basic",
+			name: "send/scheduled",
 			stdin: `From: user@club1.fr
-To: user+send-confirm@club1.fr
-Message-Id: <fakeid2@club1.fr>
-In-Reply-To: <user-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
-References: <user-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
-Subject: Send confirm
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: [Send-At: 2026-10-02 09:00] Send
+
+Content of the mail!
 `,
-			tmp: map[string]string{
-				"newsletter-send-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====.subject.txt": "Send",
-				"newsletter-send-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====.body.txt":    "Content of the mail!",
+			config: func(c *newsletter.Config) error {
+				c.Settings.TimeZone = "Europe/Paris"
+				return nil
 			},
+			expectedIssues: []newsletter.Issue{{
+				ID:        "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				Subject:   "Send",
+				Submitter: "user@club1.fr",
+				CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC),
+				SendAt:    time.Date(2026, 10, 2, 7, 0, 0, 0, time.UTC),
+			}},
 			expectedMails: []mailer.Mail{{
 				From:            "Display Name <user@club1.fr>",
-				To:              "recipient@club1.fr",
+				To:              "user@club1.fr",
+				Id:              "user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
+				ReplyTo:         "user+send-confirm@club1.fr",
 				ListId:          "Display Name <user.club1.fr>",
 				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
-				Subject:         "[Title] Send",
-				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send (preview)",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers on 2026-10-02 09:00 CEST, reply to this email with a line containing only SEND, or CANCEL to cancel it)",
 			}},
 		},
-	}
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			subTestHandle(t, c)
-		})
-	}
-}
+		{
+			name: "send/scheduled in the past",
+			stdin: `From: user@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: [Send-At: 2026-10-01 09:00] Send
 
-func subTestHandle(t *testing.T, tc *testCase) {
-	for file, content := range tc.tmp {
-		path := filepath.Join(os.TempDir(), file)
-		if content != "" {
-			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-				t.Fatalf("setup tmp: %v", err)
-			}
-		}
-		t.Cleanup(func() {
-			if err := os.Remove(path); err != nil {
-				t.Errorf("cleanup tmp: %v", err)
-			}
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.TimeZone = "Europe/Paris"
+				return nil
+			},
+			expectedIssues: []newsletter.Issue{},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send address has been rejected:\nsending time 2026-10-01 09:00 CEST is in the past\n\n-- \nBye bye",
+			}},
+			expectedErr: "sending time 2026-10-01 09:00 CEST is in the past",
+		},
+		{
+			name: "send-confirm/basic",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+References: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+SEND
+
+On Thu, 1 Oct 2026, Display Name wrote:
+> Content of the mail!
+>
+> CANCEL
+`,
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			runQueue: true,
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter confirmed",
+				Body:            "The newsletter \"Send\" has been confirmed, and is being sent to 1 subscribers.\n\n-- \nBye bye",
+			}, {
+				From:            "Display Name <user@club1.fr>",
+				To:              "recipient@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>",
+			}, {
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter sent: Send",
+				Body:            "The newsletter \"Send\" (issue KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====) has been sent to 1 subscribers in 0s.\n\n-- \nBye bye",
+			}},
+			expectedIssues: []newsletter.Issue{{
+				ID:        "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				Subject:   "Send",
+				Submitter: "user@club1.fr",
+				CreatedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 10, 8, 11, 0, 0, 0, time.UTC),
+				Thread: []string{
+					"user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
+					"fakeid2@club1.fr",
+				},
+				State:     newsletter.IssueSent,
+				QueuedAt:  time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				StartedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				SentAt:    time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "send-confirm/scheduled",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+SEND
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.TimeZone = "Europe/Paris"
+				return nil
+			},
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+				sendAt:  time.Date(2026, 10, 2, 7, 0, 0, 0, time.UTC),
+			},
+			runQueue: true,
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter confirmed",
+				Body:            "The newsletter \"Send\" has been confirmed, and will be sent to 1 subscribers on 2026-10-02 09:00 CEST.\n\n-- \nBye bye",
+			}},
+			expectedIssues: []newsletter.Issue{{
+				ID:        "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				Subject:   "Send",
+				Submitter: "user@club1.fr",
+				CreatedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 10, 8, 11, 0, 0, 0, time.UTC),
+				Thread:    []string{"user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr", "fakeid2@club1.fr"},
+				SendAt:    time.Date(2026, 10, 2, 7, 0, 0, 0, time.UTC),
+				State:     newsletter.IssueQueued,
+				QueuedAt:  time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "send-confirm/already sent",
+			stdin: `From: editor@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.6W2AKQNNL4USMMFI2KXBMREC27DYWNZZN2C3NEFI76JFEYQYOFAQ.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.Editors = []string{"editor@club1.fr"}
+				return nil
+			},
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+				state:   newsletter.IssueSent,
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter already sent",
+				Body:            "The newsletter \"Send\" has already been sent, or is being sent, so this confirmation from <editor@club1.fr> has been ignored.\n\n-- \nBye bye",
+			}},
+			expectedLog: "newsletter already sent: KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+		},
+		{
+			name: "send-confirm/expired issue",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.ConfirmExpiry = newsletter.Duration(30 * time.Minute)
+				return nil
+			},
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			expectedErr: "no pending issue with ID KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====: expired",
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send-confirm address has been rejected:\nno pending issue with ID KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====: expired at 2026-10-01 11:30:00 +0000 UTC\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send-confirm/cancel",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+> SEND
+Cancel!
+`,
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter cancelled",
+				Body:            "The newsletter \"Send\" has been cancelled, it will not be sent.\n\n-- \nBye bye",
+			}},
+			expectedIssues: []newsletter.Issue{},
+		},
+		{
+			name: "send-confirm/html reply",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+MIME-Version: 1.0
+Content-Type: text/html; charset=UTF-8
+
+<html><body><p>Cancel</p></body></html>
+`,
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter cancelled",
+				Body:            "The newsletter \"Send\" has been cancelled, it will not be sent.\n\n-- \nBye bye",
+			}},
+			expectedIssues: []newsletter.Issue{},
+		},
+		{
+			name: "send-confirm/no keyword",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Out of office
+
+I am out of office until Monday, I will send it then.
+
+-- 
+SEND
+`,
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter not sent",
+				Body:            "Your reply does not contain a line with only SEND or CANCEL, so nothing has been done. To send the newsletter \"Send\", reply to its preview with the line SEND, or with CANCEL to cancel it.\n\n-- \nBye bye",
+			}},
+			expectedLog: "no SEND or CANCEL keyword in the confirmation",
+			expectedIssues: []newsletter.Issue{{
+				ID:        "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				Subject:   "Send",
+				Submitter: "user@club1.fr",
+				CreatedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 10, 8, 11, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name: "send-confirm/legacy",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+References: <user-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+Send.
+`,
+			// legacy preview IDs are the bare hash of the issue, that
+			// can be read in the ID of any preview
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<user-KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send-confirm address has been rejected:\nIn-Reply-To verification error: expired token\n\n-- \nBye bye",
+			}},
+			expectedErr: "In-Reply-To verification error: expired token",
+		},
+		{
+			name: "admin/not owner",
+			stdin: `From: test@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Admin
+
+list
+`,
+			expectedErr:   "email From doesn't match user address",
+			expectedAddrs: []string{"recipient@club1.fr"},
+		},
+		{
+			name: "admin/basic",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Admin
+
+add test@club1.fr
+Remove recipient@club1.fr
+list
+count
+settings title New title
+
+-- 
+list
+`,
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedJournal: []newsletter.JournalEntry{
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "admin", Route: "admin", Address: "user@club1.fr", MessageID: "fakeid@club1.fr", Outcome: "ok", Detail: "5 commands awaiting confirmation"},
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				Id:              "<user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr>",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Admin commands to confirm",
+				Body:            "> add test@club1.fr\n> Remove recipient@club1.fr\n> list\n> count\n> settings title New title\n\nThese commands change the list. Reply to this email with a line\ncontaining only APPROVE to run them, or CANCEL to drop them.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "admin/confirm",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr>
+References: <fakeid@club1.fr> <user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr>
+Subject: Re: Admin commands to confirm
+
+APPROVE
+
+> > add test@club1.fr
+`,
+			config:        adminCommandsConfig,
+			expectedAddrs: []string{"test@club1.fr"},
+			expectedJournal: []newsletter.JournalEntry{
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "add", Route: "admin", Address: "test@club1.fr", MessageID: "fakeid2@club1.fr", Outcome: "ok"},
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "remove", Route: "admin", Address: "recipient@club1.fr", MessageID: "fakeid2@club1.fr", Outcome: "ok"},
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "config", Route: "admin", MessageID: "fakeid2@club1.fr", Outcome: "ok", Detail: `title: "New title"`},
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "admin", Route: "admin", Address: "user@club1.fr", MessageID: "fakeid2@club1.fr", Outcome: "ok", Detail: "5 commands, 0 failed"},
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid@club1.fr> <user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[New title] Admin report",
+				Body:            "> add test@club1.fr\naddress added: test@club1.fr\n\n> Remove recipient@club1.fr\naddress removed: recipient@club1.fr\n\n> list\ntest@club1.fr\n\n> count\n1 subscribers\n\n> settings title New title\ntitle set to \"New title\"\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "admin/cancel",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr>
+Subject: Re: Admin commands to confirm
+
+Cancel
+`,
+			config:        adminCommandsConfig,
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Admin commands cancelled",
+				Body:            "The commands have been dropped.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "admin/no keyword",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr>
+Subject: Auto: Re: Admin commands to confirm
+
+I am on holidays.
+`,
+			config:        adminCommandsConfig,
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Admin commands not confirmed",
+				Body:            "No line containing only APPROVE or CANCEL was found in your reply,\nthe commands have not been run.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "admin/already confirmed",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.admin.tm89c0.7DZFX2Q6MHMQU3QDEULWXBYT5SSPBH623NCZFEG4WBZ5EZX25EYA.TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====@club1.fr>
+Subject: Re: Admin commands to confirm
+
+APPROVE
+`,
+			expectedErr:   "take admin commands: no pending admin commands with ID TYV27B7OSNZ7F2CV5ZHLQ4PBC4I7UOQU342L6SW55ME72MC7F7WA====",
+			expectedAddrs: []string{"recipient@club1.fr"},
+		},
+		{
+			name: "admin/html",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Admin
+MIME-Version: 1.0
+Content-Type: text/html; charset=UTF-8
+
+<html><body><p>count</p></body></html>
+`,
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Admin report",
+				Body:            "> count\n1 subscribers\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "admin/french",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Admin
+
+count
+unknown
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.Language = messages.LangFrench
+				messages.SetLanguage(c.Settings.Language)
+				return nil
+			},
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Rapport d'administration",
+				Body:            "> count\n1 abonnés\n\n> unknown\nerreur : unknown command \"unknown\"\n\n" + messages.AdminHelp_body.In(messages.LangFrench) + "\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "admin/errors",
+			stdin: `From: user@club1.fr
+To: user+admin@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Re: Admin report
+
+unknown
+> list
+stats
+`,
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Admin report",
+				Body:            "> unknown\nerror: unknown command \"unknown\"\n\n> stats\nsubscribers: 1\nsubscribers of topic events: 1\nsubscribers of topic digest: 1\nsuppressed addresses: 0\nin the last 30 days:\n    subscribe-confirm: 0\n    unsubscribe: 0\n    send-confirm: 0\n\n" + messages.AdminHelp_body.In(messages.LangEnglish) + "\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "subscribe/invite-only",
+			stdin: `From: test@club1.fr
+To: user+subscribe@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Subscribe
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.SubscriptionPolicy = newsletter.SubscriptionInvite
+				return nil
+			},
+			expectedLog: "subscription refused: the list is invite-only",
+		},
+		{
+			name: "subscribe-confirm/approval",
+			stdin: `From: test@club1.fr
+To: user+subscribe-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>
+References: <user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr>
+Subject: Subscribe confirm
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.SubscriptionPolicy = newsletter.SubscriptionApprove
+				return nil
+			},
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{
+				{
+					From:            "Display Name <user@club1.fr>",
+					To:              "user@club1.fr",
+					Id:              "<user-2.approve.tm89c0.QINYK4GTCQ7OPVOGPPEOVLEDIDQW7AQ2SBX3FFCSJ6SNZ5Q56WSA.ORSXG5CAMNWHKYRRFZTHE@club1.fr>",
+					ReplyTo:         "user+moderate@club1.fr",
+					ListId:          "Display Name <user.club1.fr>",
+					ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+					Subject:         "[Title] Subscription to approve: test@club1.fr",
+					Body:            "test@club1.fr confirmed their subscription request.\n\nReply to this email with a line containing only APPROVE to approve it, or REJECT to reject it. It can also be done with the `newsletter pending approve|reject ADDRESS` command.\n\n-- \nBye bye",
+				},
+				{
+					From:            "Display Name <user@club1.fr>",
+					To:              "test@club1.fr",
+					InReplyTo:       "<fakeid2@club1.fr>",
+					References:      "<user-2.sub.tm89c0.Y6US6OPNMBRQDOKMZVG572EGYNAF7K3DQUJDAAMIMOXGFF3CDS3Q@club1.fr> <fakeid2@club1.fr>",
+					ListId:          "Display Name <user.club1.fr>",
+					ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+					Subject:         "[Title] Subscription awaiting approval",
+					Body:            "Your subscription to the newsletter [Title] is confirmed, and now awaits the approval of its owner.\n\n-- \nBye bye",
+				},
+			},
+		},
+		{
+			name: "moderate/approve",
+			stdin: `From: user@club1.fr
+To: user+moderate@club1.fr
+Message-Id: <fakeid3@club1.fr>
+In-Reply-To: <user-2.approve.tm89c0.QINYK4GTCQ7OPVOGPPEOVLEDIDQW7AQ2SBX3FFCSJ6SNZ5Q56WSA.ORSXG5CAMNWHKYRRFZTHE@club1.fr>
+Subject: Re: Subscription to approve
+
+Approve
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.SubscriptionPolicy = newsletter.SubscriptionApprove
+				return c.AddPendingSubscription(newsletter.PendingSubscription{
+					Address:     "test@club1.fr",
+					Topics:      []string{"events"},
+					RequestedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				})
+			},
+			expectedAddrs: []string{"recipient@club1.fr", "test@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Subscription is successfull !",
+				Body:            "Your email has been successfully subscribed to the newsletter [Title].\n\nTopics: events\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "moderate/reject",
+			stdin: `From: user@club1.fr
+To: user+moderate@club1.fr
+Message-Id: <fakeid3@club1.fr>
+In-Reply-To: <user-2.approve.tm89c0.QINYK4GTCQ7OPVOGPPEOVLEDIDQW7AQ2SBX3FFCSJ6SNZ5Q56WSA.ORSXG5CAMNWHKYRRFZTHE@club1.fr>
+Subject: Re: Subscription to approve
+
+Reject
+
+> test@club1.fr confirmed their subscription request
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.SubscriptionPolicy = newsletter.SubscriptionApprove
+				return c.AddPendingSubscription(newsletter.PendingSubscription{
+					Address:     "test@club1.fr",
+					Topics:      []string{"events"},
+					RequestedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				})
+			},
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedJournal: []newsletter.JournalEntry{
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "reject", Route: "moderate", Address: "test@club1.fr", MessageID: "fakeid3@club1.fr", Outcome: "ok"},
+				{Time: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), Event: "moderate", Route: "moderate", Address: "user@club1.fr", MessageID: "fakeid3@club1.fr", Outcome: "ok", Detail: "rejected test@club1.fr"},
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Subscription rejected",
+				Body:            "Your subscription to the newsletter [Title] has been rejected by its owner.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "moderate/html reply",
+			stdin: `From: user@club1.fr
+To: user+moderate@club1.fr
+Message-Id: <fakeid3@club1.fr>
+In-Reply-To: <user-2.approve.tm89c0.QINYK4GTCQ7OPVOGPPEOVLEDIDQW7AQ2SBX3FFCSJ6SNZ5Q56WSA.ORSXG5CAMNWHKYRRFZTHE@club1.fr>
+Subject: Re: Subscription to approve
+MIME-Version: 1.0
+Content-Type: text/html; charset=UTF-8
+
+<html><body><div>Reject</div></body></html>
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.SubscriptionPolicy = newsletter.SubscriptionApprove
+				return c.AddPendingSubscription(newsletter.PendingSubscription{
+					Address:     "test@club1.fr",
+					RequestedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				})
+			},
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "test@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Subscription rejected",
+				Body:            "Your subscription to the newsletter [Title] has been rejected by its owner.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "moderate/auto-reply",
+			stdin: `From: user@club1.fr
+To: user+moderate@club1.fr
+Message-Id: <fakeid3@club1.fr>
+In-Reply-To: <user-2.approve.tm89c0.QINYK4GTCQ7OPVOGPPEOVLEDIDQW7AQ2SBX3FFCSJ6SNZ5Q56WSA.ORSXG5CAMNWHKYRRFZTHE@club1.fr>
+Subject: Out of office
+
+I am away until Monday.
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.SubscriptionPolicy = newsletter.SubscriptionApprove
+				return c.AddPendingSubscription(newsletter.PendingSubscription{
+					Address:     "test@club1.fr",
+					RequestedAt: time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC),
+				})
+			},
+			expectedLog:   "no APPROVE or REJECT keyword in the moderation",
+			expectedAddrs: []string{"recipient@club1.fr"},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid3@club1.fr>",
+				References:      "<fakeid3@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Subscription not moderated",
+				Body:            "Your reply does not contain a line with only APPROVE or REJECT, so nothing has been done. To approve the subscription of <test@club1.fr>, reply to its request with the line APPROVE, or with REJECT to reject it.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "moderate/not pending",
+			stdin: `From: user@club1.fr
+To: user+moderate@club1.fr
+Message-Id: <fakeid3@club1.fr>
+In-Reply-To: <user-2.approve.tm89c0.QINYK4GTCQ7OPVOGPPEOVLEDIDQW7AQ2SBX3FFCSJ6SNZ5Q56WSA.ORSXG5CAMNWHKYRRFZTHE@club1.fr>
+Subject: Re: Subscription to approve
+
+APPROVE
+`,
+			expectedErr:   "no pending subscription for test@club1.fr",
+			expectedAddrs: []string{"recipient@club1.fr"},
+		},
+		{
+			name: "send/editor",
+			stdin: `From: editor@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.Editors = []string{"editor@club1.fr"}
+				return nil
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "editor@club1.fr",
+				Id:              "user-2.send.tm89c0.6W2AKQNNL4USMMFI2KXBMREC27DYWNZZN2C3NEFI76JFEYQYOFAQ.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
+				ReplyTo:         "user+send-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send (preview)",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers, reply to this email with a line containing only SEND, or CANCEL to cancel it)",
+			}},
+		},
+		{
+			name: "send/not editor",
+			stdin: `From: other@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.Editors = []string{"editor@club1.fr"}
+				return nil
+			},
+			expectedErr: "email From doesn't match user or editor address",
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <other@club1.fr> to the send address has been rejected:\nemail From doesn't match user or editor address\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send/not editor throttled",
+			stdin: `From: other@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				entry := "2026-10-01T11:30:00Z\tother@club1.fr\n"
+				return os.WriteFile(filepath.Join(c.Dir, newsletter.NoticeThrottleFile), []byte(entry), 0660)
+			},
+			expectedErr: "email From doesn't match user or editor address",
+			expectedLog: "rejection notice not sent: too many confirmation mails: address in cooldown for 1h0m0s",
+		},
+		{
+			name: "send-confirm/first approval",
+			stdin: `From: editor@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.6W2AKQNNL4USMMFI2KXBMREC27DYWNZZN2C3NEFI76JFEYQYOFAQ.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+send
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.Editors = []string{"editor@club1.fr"}
+				c.Settings.SendApprovals = 2
+				return nil
+			},
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "editor@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter approved",
+				Body:            "Your approval of the newsletter \"Send\" has been recorded, 1 more approvals are needed to send it.\n\n-- \nBye bye",
+			}, {
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				Id:              "user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
+				ReplyTo:         "user+send-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send (preview)",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail confirmed by editor@club1.fr, if you want to approve sending the newsletter to all the 1 subscribers, reply to this email with a line containing only SEND, or CANCEL to cancel it)",
+			}},
+		},
+		{
+			name: "send-confirm/second approval",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid3@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+SEND
+`,
+			config: func(c *newsletter.Config) error {
+				c.Settings.Editors = []string{"editor@club1.fr"}
+				c.Settings.SendApprovals = 2
+				return nil
+			},
+			issue: &testIssue{
+				id:        "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject:   "Send",
+				body:      "Content of the mail!",
+				approvals: []string{"editor@club1.fr"},
+			},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid3@club1.fr>",
+				References:      "<fakeid3@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter confirmed",
+				Body:            "The newsletter \"Send\" has been confirmed, and is being sent to 1 subscribers.\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send-secrettoken/basic",
+			stdin: `From: user@club1.fr
+To: user+send-secrettoken@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				c.SendToken = "secrettoken"
+				c.Settings.RequireSendToken = true
+				return nil
+			},
+			expectedJournal: []newsletter.JournalEntry{{
+				Time:      time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				Event:     "send",
+				Route:     "send-TOKEN",
+				Address:   "user@club1.fr",
+				MessageID: "fakeid@club1.fr",
+				Outcome:   "ok",
+			}},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				Id:              "user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr",
+				ReplyTo:         "user+send-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send (preview)",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers, reply to this email with a line containing only SEND, or CANCEL to cancel it)",
+			}},
+		},
+		{
+			name: "send-wrongtoken/basic",
+			stdin: `From: user@club1.fr
+To: user+send-wrongtoken@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				c.SendToken = "secrettoken"
+				return nil
+			},
+			expectedLog: `invalid sub command: "send-wrongtoken"`,
+		},
+		{
+			name: "send/token required",
+			stdin: `From: user@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+
+Content of the mail!
+`,
+			config: func(c *newsletter.Config) error {
+				c.SendToken = "secrettoken"
+				c.Settings.RequireSendToken = true
+				return nil
+			},
+			expectedLog: "news refused: it must be sent to the secret send address",
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send address has been rejected:\nnews refused: it must be sent to the secret send address\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send/html",
+			stdin: `From: user@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="mixed"
+
+--mixed
+Content-Type: text/html; charset=UTF-8
+
+<html><body><p>Content of the <b>mail</b>!</p></body></html>
+--mixed
+Content-Type: text/plain; name="notes.txt"
+Content-Disposition: attachment; filename="notes.txt"
+
+Some notes
+--mixed--
+`,
+			expectedIssues: []newsletter.Issue{{
+				ID:        "LH6HTDX2DASQNXLSDDZAFUIASGCUYPQYQYFECOLC5UH35ZN5TXRQ====",
+				Subject:   "Send",
+				Submitter: "user@club1.fr",
+				CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+				ExpiresAt: time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC),
+				Rich:      true,
+			}},
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				Id:              "user-2.send.tm89c0.7ADJ2QSNMTXVYGXI3KOU4C3JGQE4F563GNLXIJKF53ARFHFECXXQ.LH6HTDX2DASQNXLSDDZAFUIASGCUYPQYQYFECOLC5UH35ZN5TXRQ====@club1.fr",
+				ReplyTo:         "user+send-confirm@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send (preview)",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers, reply to this email with a line containing only SEND, or CANCEL to cancel it)",
+				HTML:            "<html><body><p>Content of the <b>mail</b>!</p><div style=\"white-space: pre-wrap\">-- \nBye bye\n\nTo unsubscribe, send a mail to &lt;user+unsubscribe@club1.fr&gt;\n\n(this is a preview mail, if you want to confirm and send the newsletter to all the 1 subscribers, reply to this email with a line containing only SEND, or CANCEL to cancel it)</div>\n</body></html>",
+				Attachments:     []mailer.Attachment{{Filename: "notes.txt", ContentType: "text/plain", Data: []byte("Some notes")}},
+			}},
+		},
+		{
+			name: "send/attachment too large",
+			stdin: `From: user@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="mixed"
+
+--mixed
+Content-Type: text/plain; charset=UTF-8
+
+Content of the mail!
+--mixed
+Content-Type: text/plain; name="notes.txt"
+Content-Disposition: attachment; filename="notes.txt"
+
+Some notes
+--mixed--
+`,
+			config: func(c *newsletter.Config) error {
+				c.System.Policy.MaxAttachmentSize = 4
+				return nil
+			},
+			expectedErr: "attachments are too large: 10 bytes in total, the maximum is 4",
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send address has been rejected:\nattachments are too large: 10 bytes in total, the maximum is 4\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send/attachments too large in total",
+			stdin: `From: user@club1.fr
+To: user+send@club1.fr
+Message-Id: <fakeid@club1.fr>
+Subject: Send
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="mixed"
+
+--mixed
+Content-Type: text/plain; charset=UTF-8
+
+Content of the mail!
+--mixed
+Content-Type: text/plain; name="notes.txt"
+Content-Disposition: attachment; filename="notes.txt"
+
+Some notes
+--mixed
+Content-Type: text/plain; name="more.txt"
+Content-Disposition: attachment; filename="more.txt"
+
+More notes
+--mixed--
+`,
+			config: func(c *newsletter.Config) error {
+				c.System.Policy.MaxAttachmentSize = 15
+				return nil
+			},
+			expectedErr: "attachments are too large: 20 bytes in total, the maximum is 15",
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid@club1.fr>",
+				References:      "<fakeid@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter request rejected",
+				Body:            "A mail from <user@club1.fr> to the send address has been rejected:\nattachments are too large: 20 bytes in total, the maximum is 15\n\n-- \nBye bye",
+			}},
+		},
+		{
+			name: "send-confirm/html",
+			stdin: `From: user@club1.fr
+To: user+send-confirm@club1.fr
+Message-Id: <fakeid2@club1.fr>
+In-Reply-To: <user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr>
+Subject: Send confirm
+
+SEND
+`,
+			issue: &testIssue{
+				id:      "KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====",
+				subject: "Send",
+				body:    "Content of the mail!",
+				message: `From: user@club1.fr
+Message-Id: <fakeid@club1.fr>
+MIME-Version: 1.0
+Content-Type: multipart/related; boundary="related"
+
+--related
+Content-Type: text/html; charset=UTF-8
+
+<p>Content of the <img src="cid:logo@club1.fr"> mail!</p>
+--related
+Content-Type: image/png
+Content-Disposition: inline
+Content-ID: <logo@club1.fr>
+Content-Transfer-Encoding: base64
+
+UE5H
+--related--
+`,
+			},
+			runQueue: true,
+			expectedMails: []mailer.Mail{{
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter confirmed",
+				Body:            "The newsletter \"Send\" has been confirmed, and is being sent to 1 subscribers.\n\n-- \nBye bye",
+			}, {
+				From:            "Display Name <user@club1.fr>",
+				To:              "recipient@club1.fr",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Send",
+				Body:            "Content of the mail!\n\n-- \nBye bye\n\nTo unsubscribe, send a mail to <user+unsubscribe@club1.fr>",
+				HTML:            "<p>Content of the <img src=\"cid:logo@club1.fr\"> mail!</p>\n<div style=\"white-space: pre-wrap\">-- \nBye bye\n\nTo unsubscribe, send a mail to &lt;user+unsubscribe@club1.fr&gt;</div>\n",
+				Attachments:     []mailer.Attachment{{ContentType: "image/png", ContentID: "logo@club1.fr", Data: []byte("PNG")}},
+			}, {
+				From:            "Display Name <user@club1.fr>",
+				To:              "user@club1.fr",
+				InReplyTo:       "<fakeid2@club1.fr>",
+				References:      "<user-2.send.tm89c0.OX3H3775W6ZCPQ7BT4RH3RGC5ZKJZO4S75ZLJ5KTE4SCAA527FBA.KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====@club1.fr> <fakeid2@club1.fr>",
+				ListId:          "Display Name <user.club1.fr>",
+				ListUnsubscribe: "<mailto:user+unsubscribe@club1.fr>",
+				Subject:         "[Title] Newsletter sent: Send",
+				Body:            "The newsletter \"Send\" (issue KAV4QKP2PFXLWHG5XM3E6X23PROVB5DGNDSABUPA6XQIODZDJ6UA====) has been sent to 1 subscribers in 0s.\n\n-- \nBye bye",
+			}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			subTestHandle(t, c)
 		})
 	}
+}
+
+func subTestHandle(t *testing.T, tc *testCase) {
+	config := tc.config
+	if tc.issue != nil {
+		config = func(c *newsletter.Config) error {
+			if tc.config != nil {
+				if err := tc.config(c); err != nil {
+					return err
+				}
+			}
+			return createTestIssue(c, tc.issue)
+		}
+	}
 
 	route := path.Dir(tc.name)
-	c, syslog, mail, err := handle(t, route, tc.stdin)
-	if err != nil {
+	c, syslog, mail, err := handle(t, route, tc.stdin, config, tc.runQueue)
+	if tc.expectedErr == "" && err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
+	if tc.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedErr)) {
+		t.Errorf("expected error containing %q, got: %v", tc.expectedErr, err)
+	}
 
 	log := strings.TrimSpace(syslog.String())
 	if !strings.Contains(log, tc.expectedLog) {
@@ -252,8 +1727,32 @@ func subTestHandle(t *testing.T, tc *testCase) {
 	}
 
 	if tc.expectedAddrs != nil {
-		if !reflect.DeepEqual(c.nl.Config.Emails, tc.expectedAddrs) {
-			t.Errorf("expected subscribed addrs:\n%#v\ngot:\n%#v", tc.expectedAddrs, c.nl.Config.Emails)
+		addrs, err := newsletter.Addresses(c.nl.Config.Subscribers)
+		if err != nil {
+			t.Errorf("list subscribers: %v", err)
+		}
+		if !reflect.DeepEqual(addrs, tc.expectedAddrs) {
+			t.Errorf("expected subscribed addrs:\n%#v\ngot:\n%#v", tc.expectedAddrs, addrs)
+		}
+	}
+
+	if tc.expectedJournal != nil {
+		journal, err := c.nl.Journal(newsletter.JournalFilter{})
+		if err != nil {
+			t.Errorf("read journal: %v", err)
+		}
+		if !reflect.DeepEqual(journal, tc.expectedJournal) {
+			t.Errorf("expected journal:\n%#v\ngot:\n%#v", tc.expectedJournal, journal)
+		}
+	}
+
+	if tc.expectedIssues != nil {
+		issues, err := c.nl.Issues()
+		if err != nil {
+			t.Errorf("list issues: %v", err)
+		}
+		if (len(issues) > 0 || len(tc.expectedIssues) > 0) && !reflect.DeepEqual(issues, tc.expectedIssues) {
+			t.Errorf("expected issues:\n%#v\ngot:\n%#v", tc.expectedIssues, issues)
 		}
 	}
 
@@ -261,3 +1760,36 @@ func subTestHandle(t *testing.T, tc *testCase) {
 		t.Errorf("expected mail:\n%#v\ngot:\n%#v", tc.expectedMails, mail)
 	}
 }
+
+func createTestIssue(c *newsletter.Config, ti *testIssue) error {
+	nl := &newsletter.Newsletter{Config: c, Now: func() time.Time {
+		return time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC)
+	}}
+	var message []byte
+	if ti.message != "" {
+		message = []byte(ti.message)
+	}
+	issue := &newsletter.Issue{ID: ti.id, Subject: ti.subject, Submitter: "user@club1.fr", SendAt: ti.sendAt}
+	if err := nl.CreateIssue(issue, ti.body, message); err != nil {
+		return err
+	}
+	for _, editor := range ti.approvals {
+		if _, err := nl.ApproveIssue(issue, editor); err != nil {
+			return err
+		}
+	}
+	if ti.state != newsletter.IssuePending {
+		if err := nl.QueueIssue(issue, &mailer.Mail{}); err != nil {
+			return err
+		}
+	}
+	if ti.state == newsletter.IssueSent {
+		nl.Mailer = &mailertest.Mailer{Handler: func(m *mailer.Mail) error { return nil }}
+		for _, err := range nl.RunQueue() {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
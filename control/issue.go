@@ -0,0 +1,115 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package control
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/mnako/letters"
+)
+
+// bodyText returns the text body of the email, converted from its HTML
+// body if it has no text body, as sent by some webmails.
+func bodyText(email *letters.Email) string {
+	if strings.TrimSpace(email.Text) == "" && email.HTML != "" {
+		return newsletter.HTMLToText(email.HTML)
+	}
+	return email.Text
+}
+
+// issueAttachments returns the inline and attached files of the submitted
+// issue.
+func issueAttachments(email *letters.Email) []mailer.Attachment {
+	var attachments []mailer.Attachment
+	for _, f := range email.InlineFiles {
+		attachments = append(attachments, mailer.Attachment{
+			Filename:    fileName(f.ContentType, f.ContentDisposition),
+			ContentType: f.ContentType.ContentType,
+			ContentID:   f.ContentID,
+			Data:        f.Data,
+		})
+	}
+	for _, f := range email.AttachedFiles {
+		attachments = append(attachments, mailer.Attachment{
+			Filename:    fileName(f.ContentType, f.ContentDisposition),
+			ContentType: f.ContentType.ContentType,
+			Data:        f.Data,
+		})
+	}
+	return attachments
+}
+
+func fileName(ct letters.ContentTypeHeader, cd letters.ContentDispositionHeader) string {
+	if name := cd.Params["filename"]; name != "" {
+		return name
+	}
+	return ct.Params["name"]
+}
+
+// checkAttachments checks the total size of the attachments against the
+// policy of the server.
+func (c *Controller) checkAttachments(attachments []mailer.Attachment) error {
+	max := c.nl.Config.System.Policy.MaxAttachmentSize
+	if max <= 0 {
+		return nil
+	}
+	var size int64
+	for _, a := range attachments {
+		size += int64(len(a.Data))
+	}
+	if size > max {
+		return fmt.Errorf("attachments are too large: %v bytes in total, the maximum is %v", size, max)
+	}
+	return nil
+}
+
+// issueMessage parses the submitted message of the given issue, that is
+// only kept when it has an HTML body or files. It returns nil if there is
+// none.
+func (c *Controller) issueMessage(issue *newsletter.Issue) (*letters.Email, error) {
+	raw, err := c.nl.IssueMessage(issue)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	req, err := ParseRequest(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("parse issue message: %w", err)
+	}
+	return &req.Email, nil
+}
+
+// addRichContent adds the HTML body and the files of the submitted issue to
+// the mail, the HTML body ending with the signature and the given trailer.
+func (c *Controller) addRichContent(mail *mailer.Mail, email *letters.Email, trailer string) {
+	if email == nil {
+		return
+	}
+	if email.HTML != "" {
+		if c.nl.Config.Signature != "" {
+			trailer = "-- \n" + c.nl.Config.Signature + trailer
+		}
+		mail.HTML = newsletter.AppendHTML(email.HTML, strings.TrimLeft(trailer, "\n"))
+	}
+	mail.Attachments = issueAttachments(email)
+}
@@ -0,0 +1,105 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package control
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// requestApproval puts the confirmed subscription of the request in the
+// pending ones, and asks the owner to approve it.
+func (c *Controller) requestApproval(req *Request, topics []string) error {
+	p := &newsletter.PendingSubscription{
+		Address:     req.From.Address,
+		Topics:      topics,
+		RequestedAt: c.now().UTC().Truncate(time.Second),
+	}
+	if err := c.nl.Config.AddPendingSubscription(*p); err != nil {
+		return fmt.Errorf("add pending subscription: %w", err)
+	}
+	c.log.Infof("subscription of %q awaits approval", req.From.Address)
+	c.entry.Detail = "awaiting approval"
+	if len(topics) > 0 {
+		c.entry.Detail = topicsDetail(topics) + ", " + c.entry.Detail
+	}
+
+	if err := c.nl.Mailer.Send(c.nl.ApprovalRequestMail(p)); err != nil {
+		return fmt.Errorf("send approval request mail: %w", err)
+	}
+	c.sendResponse(
+		req,
+		messages.SubscriptionPending_subject.Print(),
+		c.nl.TitledBody(messages.SubscriptionPending_body, messages.SubscriptionPendingAlt_body),
+	)
+	return nil
+}
+
+// moderate approves or rejects the pending subscription whose approval
+// request the owner replied to, according to the keyword of the reply.
+// Replies without keyword, like auto-replies, do nothing.
+func (c *Controller) moderate(req *Request) error {
+	if req.From.Address != c.nl.LocalUserAddr() {
+		return fmt.Errorf("email From doesn't match user address")
+	}
+	if len(req.Headers.InReplyTo) == 0 {
+		return fmt.Errorf("missing In-Reply-To header")
+	}
+	addr, err := c.nl.VerifyApprovalID(string(req.Headers.InReplyTo[0]))
+	if err != nil {
+		return fmt.Errorf("In-Reply-To verification error: %w", err)
+	}
+
+	switch c.nl.ConfirmAction(bodyText(&req.Email), newsletter.ActionApprove, newsletter.ActionReject) {
+	case "":
+		approve, reject := c.nl.ActionKeyword(newsletter.ActionApprove), c.nl.ActionKeyword(newsletter.ActionReject)
+		c.reject("no %s or %s keyword in the moderation", approve, reject)
+		c.sendResponse(req, messages.ApprovalNoKeyword_subject.Print(), fmt.Sprintf(messages.ApprovalNoKeyword_body.Print(), approve, reject, addr))
+		return nil
+	case newsletter.ActionReject:
+		p, err := c.nl.RejectSubscription(addr)
+		c.recordChange(req, newsletter.EventReject, addr, "", err)
+		if err != nil {
+			return fmt.Errorf("reject subscription: %w", err)
+		}
+		c.entry.Detail = "rejected " + addr
+		c.log.Infof("subscription of %q rejected", addr)
+		if err := c.nl.Mailer.Send(c.nl.RejectedMail(p)); err != nil {
+			return fmt.Errorf("send rejection mail: %w", err)
+		}
+		return nil
+	}
+
+	p, err := c.nl.ApproveSubscription(addr)
+	if err != nil {
+		c.recordChange(req, newsletter.EventApprove, addr, "", err)
+		return fmt.Errorf("approve subscription: %w", err)
+	}
+	c.recordChange(req, newsletter.EventApprove, addr, topicsDetail(p.Topics), nil)
+	c.entry.Detail = "approved " + addr
+	c.log.Infof("subscription of %q approved", addr)
+	if err := c.nl.Mailer.Send(c.nl.ApprovedMail(p)); err != nil {
+		return fmt.Errorf("send approval mail: %w", err)
+	}
+	return nil
+}
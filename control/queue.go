@@ -0,0 +1,78 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package control
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// RouteRunQueue is the pseudo route that runs the queue, instead of
+// handling a request.
+const RouteRunQueue = "run-queue"
+
+// startWorker starts a detached newsletterctl process that runs the queue
+// of the list with the given name, stored in the given base config
+// directory. It is not bound to the MTA pipe of the request, so the
+// distribution is not interrupted by its time limit.
+func startWorker(base string, list string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var args []string
+	if base != "" {
+		args = append(args, "-C", base)
+	}
+	if list != "" {
+		args = append(args, "-list", list)
+	}
+	cmd := exec.Command(exe, append(args, RouteRunQueue)...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// RunQueue sends the queued issues and logs the result of each of them.
+func (c *Controller) RunQueue() error {
+	c.log.AddContext(fmt.Sprintf("route %q", RouteRunQueue))
+	var errCount int
+	for d, err := range c.nl.RunQueue() {
+		if err != nil {
+			c.log.Errorf("error: %v", err)
+			errCount++
+			continue
+		}
+		failures := len(d.Failures())
+		if failures > 0 {
+			c.log.Warningf("issue %s sent to %v subscribers with %v error(s)", d.Issue.ID, len(d.Deliveries), failures)
+		} else {
+			c.log.Infof("issue %s successfully sent to all the %v subscribers", d.Issue.ID, len(d.Deliveries))
+		}
+	}
+	if errCount > 0 {
+		return fmt.Errorf("%v issue(s) could not be sent", errCount)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@
 package control
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"net/mail"
@@ -35,10 +36,16 @@ type Request struct {
 	letters.Email
 	From      *mail.Address
 	MessageID string
+	// Raw is the message as received.
+	Raw []byte
 }
 
 func ParseRequest(r io.Reader) (*Request, error) {
-	email, err := letters.ParseEmail(r)
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	email, err := letters.ParseEmail(bytes.NewReader(raw))
 	if err != nil {
 		return nil, err
 	}
@@ -52,5 +59,6 @@ func ParseRequest(r io.Reader) (*Request, error) {
 		Email:     email,
 		From:      email.Headers.From[0],
 		MessageID: string(email.Headers.MessageID),
+		Raw:       raw,
 	}, nil
 }
@@ -0,0 +1,52 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"slices"
+	"strings"
+)
+
+// Editors returns the addresses allowed to send news by email: the address
+// of the owner, followed by the ones of [Settings.Editors].
+func (nl *Newsletter) Editors() []string {
+	editors := []string{nl.LocalUserAddr()}
+	for _, editor := range nl.Config.Settings.Editors {
+		if !slices.ContainsFunc(editors, func(e string) bool { return strings.EqualFold(e, editor) }) {
+			editors = append(editors, editor)
+		}
+	}
+	return editors
+}
+
+// IsEditor reports whether the given address is allowed to send news by
+// email.
+func (nl *Newsletter) IsEditor(addr string) bool {
+	return slices.ContainsFunc(nl.Editors(), func(e string) bool { return strings.EqualFold(e, addr) })
+}
+
+// SendApprovalsOrDefault returns [Settings.SendApprovals], or 1 if it is
+// not set.
+func (s *Settings) SendApprovalsOrDefault() int {
+	if s.SendApprovals <= 0 {
+		return 1
+	}
+	return s.SendApprovals
+}
@@ -0,0 +1,48 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditors(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.Editors = []string{"editor@club1.fr", "User@club1.fr"}
+
+	expected := []string{"user@club1.fr", "editor@club1.fr"}
+	if editors := nl.Editors(); !reflect.DeepEqual(editors, expected) {
+		t.Errorf("expected editors %v, got %v", expected, editors)
+	}
+	cases := []struct {
+		addr     string
+		expected bool
+	}{
+		{"user@club1.fr", true},
+		{"Editor@Club1.fr", true},
+		{"other@club1.fr", false},
+	}
+	for _, c := range cases {
+		if isEditor := nl.IsEditor(c.addr); isEditor != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.addr, c.expected, isEditor)
+		}
+	}
+}
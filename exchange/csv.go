@@ -0,0 +1,161 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package exchange
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+const (
+	colAddress = iota
+	colName
+	colLanguage
+	colSubscribedAt
+)
+
+var csvHeader = []string{"address", "name", "language", "subscribed-at"}
+
+// csvColumns maps the accepted header names to their column.
+var csvColumns = map[string]int{
+	"address":       colAddress,
+	"email":         colAddress,
+	"e-mail":        colAddress,
+	"mail":          colAddress,
+	"name":          colName,
+	"display name":  colName,
+	"language":      colLanguage,
+	"lang":          colLanguage,
+	"subscribed-at": colSubscribedAt,
+	"subscribed_at": colSubscribedAt,
+	"subscribed":    colSubscribedAt,
+	"date":          colSubscribedAt,
+}
+
+var dateLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %q", s)
+}
+
+// readCSV reads subscribers from CSV records. If the first record does not
+// contain an address, it is used as a header to find the columns, otherwise
+// the columns are expected in the order of [csvHeader].
+func readCSV(r io.Reader) ([]newsletter.Subscriber, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// index of each column in the records, -1 if missing
+	columns := []int{0, 1, 2, 3}
+	var subs []newsletter.Subscriber
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && !strings.Contains(record[0], "@") {
+			columns, err = parseCSVHeader(record)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		field := func(col int) string {
+			i := columns[col]
+			if i == -1 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if field(colAddress) == "" {
+			continue
+		}
+		addr, err := parseAddress(field(colAddress))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		sub := newsletter.Subscriber{
+			Address:  addr.Address,
+			Name:     field(colName),
+			Language: messages.Language(strings.ToLower(field(colLanguage))),
+		}
+		if sub.Name == "" {
+			sub.Name = addr.Name
+		}
+		if date := field(colSubscribedAt); date != "" {
+			sub.SubscribedAt, err = parseDate(date)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+func parseCSVHeader(record []string) ([]int, error) {
+	columns := []int{-1, -1, -1, -1}
+	for i, name := range record {
+		col, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]
+		if ok && columns[col] == -1 {
+			columns[col] = i
+		}
+	}
+	if columns[colAddress] == -1 {
+		return nil, fmt.Errorf("missing address column in CSV header: %q", record)
+	}
+	return columns, nil
+}
+
+func writeCSV(w io.Writer, subs []newsletter.Subscriber) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		var date string
+		if !sub.SubscribedAt.IsZero() {
+			date = sub.SubscribedAt.Format(time.RFC3339)
+		}
+		record := []string{sub.Address, sub.Name, string(sub.Language), date}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,154 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+// Package exchange reads and writes lists of subscribers in formats used
+// by other tools, to migrate them from or hand them to other programs.
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/mail"
+	"path/filepath"
+	"strings"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+type Format string
+
+const (
+	// FormatPlain is a list of addresses, one per line. Lines may also
+	// contain a display name in the form `Name <address>`.
+	FormatPlain Format = "plain"
+	// FormatCSV is a CSV file with the columns address, name, language
+	// and subscribed-at.
+	FormatCSV Format = "csv"
+	// FormatVCard is a list of vCards, from which the EMAIL, FN and LANG
+	// properties are used.
+	FormatVCard Format = "vcard"
+	// FormatMbox is a mailbox, from which the From addresses of the
+	// messages are extracted. It can only be read.
+	FormatMbox Format = "mbox"
+)
+
+var Formats = [...]Format{FormatPlain, FormatCSV, FormatVCard, FormatMbox}
+
+// DetectFormat guesses the format of a file from its extension, falling
+// back to [FormatPlain].
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".vcf", ".vcard":
+		return FormatVCard
+	case ".mbox", ".mbx":
+		return FormatMbox
+	default:
+		return FormatPlain
+	}
+}
+
+// Read parses the subscribers from r in the given format. Duplicate
+// addresses are only returned once.
+func Read(r io.Reader, format Format) ([]newsletter.Subscriber, error) {
+	var subs []newsletter.Subscriber
+	var err error
+	switch format {
+	case FormatPlain:
+		subs, err = readPlain(r)
+	case FormatCSV:
+		subs, err = readCSV(r)
+	case FormatVCard:
+		subs, err = readVCard(r)
+	case FormatMbox:
+		subs, err = readMbox(r)
+	default:
+		return nil, fmt.Errorf("unknown format: %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dedup(subs), nil
+}
+
+// Write writes the subscribers to w in the given format.
+func Write(w io.Writer, format Format, subs []newsletter.Subscriber) error {
+	switch format {
+	case FormatPlain:
+		return writePlain(w, subs)
+	case FormatCSV:
+		return writeCSV(w, subs)
+	case FormatVCard:
+		return writeVCard(w, subs)
+	case FormatMbox:
+		return fmt.Errorf("format %q cannot be written", format)
+	default:
+		return fmt.Errorf("unknown format: %q", format)
+	}
+}
+
+func dedup(subs []newsletter.Subscriber) []newsletter.Subscriber {
+	seen := make(map[string]bool, len(subs))
+	res := subs[:0]
+	for _, sub := range subs {
+		if seen[sub.Address] {
+			continue
+		}
+		seen[sub.Address] = true
+		res = append(res, sub)
+	}
+	return res
+}
+
+// parseAddress parses an address, with an optional display name.
+func parseAddress(s string) (*mail.Address, error) {
+	addr, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	return addr, nil
+}
+
+func readPlain(r io.Reader) ([]newsletter.Subscriber, error) {
+	var subs []newsletter.Subscriber
+	scanner := bufio.NewScanner(r)
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addr, err := parseAddress(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i, err)
+		}
+		subs = append(subs, newsletter.Subscriber{Address: addr.Address, Name: addr.Name})
+	}
+	return subs, scanner.Err()
+}
+
+func writePlain(w io.Writer, subs []newsletter.Subscriber) error {
+	for _, sub := range subs {
+		if _, err := fmt.Fprintln(w, sub.Address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package exchange_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/exchange"
+)
+
+func TestRead(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   exchange.Format
+		input    string
+		expected []newsletter.Subscriber
+	}{
+		{
+			"plain",
+			exchange.FormatPlain,
+			"# comment\na@club1.fr\n\nBé <b@club1.fr>\na@club1.fr\n",
+			[]newsletter.Subscriber{
+				{Address: "a@club1.fr"},
+				{Address: "b@club1.fr", Name: "Bé"},
+			},
+		},
+		{
+			"csv/header",
+			exchange.FormatCSV,
+			"Lang,E-mail,Name\nFR,a@club1.fr,\"Doe, John\"\n,b@club1.fr,\n",
+			[]newsletter.Subscriber{
+				{Address: "a@club1.fr", Name: "Doe, John", Language: "fr"},
+				{Address: "b@club1.fr"},
+			},
+		},
+		{
+			"csv/no header",
+			exchange.FormatCSV,
+			"a@club1.fr,A,en,2026-01-02\nb@club1.fr\n",
+			[]newsletter.Subscriber{
+				{Address: "a@club1.fr", Name: "A", Language: "en", SubscribedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Address: "b@club1.fr"},
+			},
+		},
+		{
+			"vcard",
+			exchange.FormatVCard,
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Doe\\, Jo\r\n hn\r\nitem1.EMAIL;TYPE=INTERNET:a@club1.fr\r\nEMAIL:other@club1.fr\r\nLANG:fr-FR\r\nEND:VCARD\r\n" +
+				"BEGIN:VCARD\r\nFN:No email\r\nEND:VCARD\r\n",
+			[]newsletter.Subscriber{
+				{Address: "a@club1.fr", Name: "Doe, John", Language: "fr"},
+			},
+		},
+		{
+			"mbox",
+			exchange.FormatMbox,
+			"From a@club1.fr Thu Jan  1 00:00:00 2026\nFrom: A <a@club1.fr>\nSubject: Hello\n\nFrom the body\n\n" +
+				"From b@club1.fr Thu Jan  1 00:00:00 2026\nFrom: =?utf-8?q?B=C3=A9?= <b@club1.fr>\n\nbody\n" +
+				"From a@club1.fr Thu Jan  1 00:00:00 2026\nFrom: a@club1.fr\n",
+			[]newsletter.Subscriber{
+				{Address: "a@club1.fr", Name: "A"},
+				{Address: "b@club1.fr", Name: "Bé"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := exchange.Read(strings.NewReader(c.input), c.format)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected:\n%#v\ngot:\n%#v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	cases := []struct {
+		name   string
+		format exchange.Format
+		input  string
+	}{
+		{"plain", exchange.FormatPlain, "not an address\n"},
+		{"csv/header", exchange.FormatCSV, "name,language\nA,fr\n"},
+		{"csv/date", exchange.FormatCSV, "a@club1.fr,A,en,yesterday\n"},
+		{"unknown", "xml", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := exchange.Read(strings.NewReader(c.input), c.format)
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	subs := []newsletter.Subscriber{
+		{Address: "a@club1.fr", Name: "Doe; John", Language: "fr", SubscribedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Address: "b@club1.fr"},
+	}
+	cases := []struct {
+		format   exchange.Format
+		expected []newsletter.Subscriber
+	}{
+		{exchange.FormatPlain, []newsletter.Subscriber{{Address: "a@club1.fr"}, {Address: "b@club1.fr"}}},
+		{exchange.FormatCSV, subs},
+		{exchange.FormatVCard, []newsletter.Subscriber{
+			{Address: "a@club1.fr", Name: "Doe; John", Language: "fr"},
+			{Address: "b@club1.fr", Name: "b@club1.fr"},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(string(c.format), func(t *testing.T) {
+			var buf strings.Builder
+			if err := exchange.Write(&buf, c.format, subs); err != nil {
+				t.Fatalf("write: unexpected error: %v", err)
+			}
+			actual, err := exchange.Read(strings.NewReader(buf.String()), c.format)
+			if err != nil {
+				t.Errorf("read: unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("expected:\n%#v\ngot:\n%#v", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package exchange
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/mail"
+	"strings"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+// readMbox extracts the From addresses of the messages of an mbox.
+// Messages without a valid From header are skipped.
+func readMbox(r io.Reader) ([]newsletter.Subscriber, error) {
+	var subs []newsletter.Subscriber
+	reader := bufio.NewReader(r)
+	var header strings.Builder
+	inHeader := false
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		eof := errors.Is(err, io.EOF)
+
+		switch {
+		case strings.HasPrefix(line, "From "):
+			header.Reset()
+			inHeader = true
+		case inHeader && (strings.TrimRight(line, "\r\n") == "" || eof):
+			header.WriteString(line)
+			subs = append(subs, fromAddresses(header.String())...)
+			inHeader = false
+		case inHeader:
+			header.WriteString(line)
+		}
+
+		if eof {
+			return subs, nil
+		}
+	}
+}
+
+func fromAddresses(header string) []newsletter.Subscriber {
+	msg, err := mail.ReadMessage(strings.NewReader(header + "\n"))
+	if err != nil {
+		return nil
+	}
+	addrs, err := msg.Header.AddressList("From")
+	if err != nil {
+		return nil
+	}
+	subs := make([]newsletter.Subscriber, len(addrs))
+	for i, addr := range addrs {
+		subs[i] = newsletter.Subscriber{Address: addr.Address, Name: addr.Name}
+	}
+	return subs
+}
@@ -0,0 +1,124 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package exchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+
+var vcardUnescaper = strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, "\n", `\N`, "\n")
+
+// unfoldLines returns the logical lines of a vCard stream, as defined by
+// RFC 6350 section 3.2.
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseProperty splits a vCard content line into its upper-cased name,
+// without group and parameters, and its value.
+func parseProperty(line string) (string, string, bool) {
+	name, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", "", false
+	}
+	name, _, _ = strings.Cut(name, ";")
+	if _, after, grouped := strings.Cut(name, "."); grouped {
+		name = after
+	}
+	return strings.ToUpper(strings.TrimSpace(name)), value, true
+}
+
+func readVCard(r io.Reader) ([]newsletter.Subscriber, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []newsletter.Subscriber
+	var sub *newsletter.Subscriber
+	for i, line := range lines {
+		name, value, ok := parseProperty(line)
+		if !ok {
+			continue
+		}
+		value = vcardUnescaper.Replace(value)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			sub = &newsletter.Subscriber{}
+		case sub == nil:
+			continue
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			if sub.Address != "" {
+				subs = append(subs, *sub)
+			}
+			sub = nil
+		case name == "EMAIL" && sub.Address == "":
+			addr, err := parseAddress(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			sub.Address = addr.Address
+		case name == "FN":
+			sub.Name = strings.TrimSpace(value)
+		case name == "LANG" && sub.Language == "":
+			lang, _, _ := strings.Cut(strings.TrimSpace(value), "-")
+			sub.Language = messages.Language(strings.ToLower(lang))
+		}
+	}
+	return subs, nil
+}
+
+func writeVCard(w io.Writer, subs []newsletter.Subscriber) error {
+	for _, sub := range subs {
+		// FN is mandatory in vCard 4.0.
+		name := sub.Name
+		if name == "" {
+			name = sub.Address
+		}
+		fmt.Fprint(w, "BEGIN:VCARD\r\nVERSION:4.0\r\n")
+		fmt.Fprintf(w, "FN:%s\r\n", vcardEscaper.Replace(name))
+		fmt.Fprintf(w, "EMAIL:%s\r\n", vcardEscaper.Replace(sub.Address))
+		if sub.Language != "" {
+			fmt.Fprintf(w, "LANG:%s\r\n", sub.Language)
+		}
+		if _, err := fmt.Fprint(w, "END:VCARD\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
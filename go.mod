@@ -5,6 +5,8 @@ go 1.24.2
 require (
 	charm.land/huh/v2 v2.0.0
 	github.com/mnako/letters v0.2.6
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/net v0.38.0
 )
 
 require (
@@ -30,7 +32,6 @@ require (
 	github.com/muesli/cancelreader v0.2.2 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
-	golang.org/x/net v0.38.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.41.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
@@ -0,0 +1,145 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+	bodyEndRegexp    = regexp.MustCompile(`(?i)</body\s*>`)
+)
+
+// HTMLToText converts the given HTML document to plain text, for the mails
+// that have no text version.
+func HTMLToText(s string) string {
+	var b strings.Builder
+	var href string
+	skip := 0
+	pre := 0
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		switch tt {
+		case xhtml.TextToken:
+			if skip > 0 {
+				continue
+			}
+			if pre > 0 {
+				b.WriteString(tok.Data)
+				continue
+			}
+			// whitespace is collapsed to a single space, dropped at the
+			// start of the lines
+			written := b.String()
+			if strings.TrimLeft(tok.Data, " \t\r\n") != tok.Data &&
+				written != "" && !strings.HasSuffix(written, "\n") && !strings.HasSuffix(written, " ") {
+				b.WriteString(" ")
+			}
+			text := strings.Join(strings.Fields(tok.Data), " ")
+			b.WriteString(text)
+			if text != "" && strings.TrimRight(tok.Data, " \t\r\n") != tok.Data {
+				b.WriteString(" ")
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			switch tok.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if tt == xhtml.StartTagToken {
+					skip++
+				}
+			case atom.Br:
+				b.WriteString("\n")
+			case atom.Pre:
+				pre++
+				b.WriteString("\n\n")
+			case atom.Li:
+				b.WriteString("\n- ")
+			case atom.Hr:
+				b.WriteString("\n\n---\n\n")
+			case atom.A:
+				href = ""
+				for _, attr := range tok.Attr {
+					if attr.Key == "href" && !strings.HasPrefix(attr.Val, "#") {
+						href = attr.Val
+					}
+				}
+			case atom.Img:
+				for _, attr := range tok.Attr {
+					if attr.Key == "alt" && attr.Val != "" {
+						b.WriteString("[" + attr.Val + "]")
+					}
+				}
+			case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+				atom.Ul, atom.Ol, atom.Table, atom.Tr, atom.Blockquote:
+				b.WriteString("\n\n")
+			}
+		case xhtml.EndTagToken:
+			switch tok.DataAtom {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if skip > 0 {
+					skip--
+				}
+			case atom.Pre:
+				if pre > 0 {
+					pre--
+				}
+				b.WriteString("\n\n")
+			case atom.A:
+				if href != "" && !strings.HasSuffix(strings.TrimSpace(b.String()), href) {
+					b.WriteString(" <" + href + ">")
+				}
+				href = ""
+			case atom.Td, atom.Th:
+				b.WriteString(" ")
+			case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+				atom.Ul, atom.Ol, atom.Table, atom.Tr, atom.Blockquote:
+				b.WriteString("\n\n")
+			}
+		}
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	text := blankLinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// AppendHTML appends the given text to the HTML document, before the end
+// of its body if any, preserving its line breaks.
+func AppendHTML(doc string, text string) string {
+	block := `<div style="white-space: pre-wrap">` + html.EscapeString(text) + "</div>\n"
+	if loc := bodyEndRegexp.FindAllStringIndex(doc, -1); len(loc) > 0 {
+		i := loc[len(loc)-1][0]
+		return doc[:i] + block + doc[i:]
+	}
+	return doc + "\n" + block
+}
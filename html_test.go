@@ -0,0 +1,75 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestHTMLToText(t *testing.T) {
+	cases := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"inline", "Some <b>bold</b>   text", "Some bold text"},
+		{
+			"document",
+			`<html><head><title>Title</title><style>p { color: red; }</style></head><body>
+  <h1>Hello   world</h1>
+  <p>A <a href="https://club1.fr">link</a>.<br>New line</p>
+  <ul><li>one</li><li> two </li></ul>
+  <pre>  code
+  here</pre>
+  <p><a href="https://club1.fr">https://club1.fr</a> <img alt="logo" src="cid:logo"></p>
+</body></html>`,
+			"Hello world\n\nA link <https://club1.fr>.\nNew line\n\n- one\n- two\n\n  code\n  here\n\nhttps://club1.fr [logo]",
+		},
+		{"entities", "<p>Caf&eacute; &amp; cr&egrave;me</p>", "Café & crème"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if text := newsletter.HTMLToText(c.html); text != c.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", c.expected, text)
+			}
+		})
+	}
+}
+
+func TestAppendHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{"fragment", "<p>News</p>", "<p>News</p>\n<div style=\"white-space: pre-wrap\">-- \nBye &lt;bye&gt;</div>\n"},
+		{"document", "<html><body><p>News</p></BODY></html>", "<html><body><p>News</p><div style=\"white-space: pre-wrap\">-- \nBye &lt;bye&gt;</div>\n</BODY></html>"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if doc := newsletter.AppendHTML(c.doc, "-- \nBye <bye>"); doc != c.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", c.expected, doc)
+			}
+		})
+	}
+}
@@ -0,0 +1,390 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// EventDiscard is the event of the journal about discarded pending issues.
+const EventDiscard = "discard"
+
+// States of the issues.
+const (
+	// IssuePending is the state of the issues awaiting confirmation.
+	IssuePending = ""
+	IssueQueued  = "queued"
+	IssueSending = "sending"
+	IssueSent    = "sent"
+)
+
+// Actions of the replies to the previews of the issues and to the approval
+// requests of the subscriptions, given by their keyword.
+const (
+	ActionSend    = "send"
+	ActionCancel  = "cancel"
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+)
+
+// Files of a pending issue, in its directory of [IssuesDir].
+const (
+	issueMetaFile    = "issue.json"
+	issueBodyFile    = "body.txt"
+	issueMessageFile = "message.eml"
+	// issueQueuedFile is created exclusively by the process that queues
+	// the issue, and is kept afterwards.
+	issueQueuedFile = "queued"
+)
+
+// Some error values.
+var (
+	// ErrNoIssue is returned when there is no pending issue with a given
+	// ID, or when it has expired.
+	ErrNoIssue = errors.New("no pending issue")
+	// ErrIssueSent is returned when queuing an issue that has already
+	// been queued or sent.
+	ErrIssueSent = errors.New("issue already sent")
+)
+
+// Issue is a news submitted by email that awaits the confirmation of the
+// editors. It is stored with its content in a private directory of
+// [IssuesDir], until it is discarded or expired. Sent issues are kept until
+// they expire, so that they are not sent again.
+type Issue struct {
+	// ID is the hash of the content of the issue, as found in the
+	// Message-ID of its preview mails.
+	ID        string `json:"-"`
+	Subject   string
+	Submitter string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// Approvals are the addresses of the editors that confirmed it.
+	Approvals []string `json:",omitempty"`
+	// Rich reports whether the submitted message is kept, for its HTML
+	// body or its files.
+	Rich bool `json:",omitempty"`
+	// Thread are the Message-IDs of the mails of the thread of the
+	// preview, the last one being the confirmation.
+	Thread []string `json:",omitempty"`
+	// Segment is the topic of the subscribers the issue is sent to, all
+	// of them if empty.
+	Segment string `json:",omitempty"`
+	// SendAt is the time before which the queued issue is not sent, see
+	// [Newsletter.ParseSendAt].
+	SendAt time.Time `json:",omitzero"`
+	// Attempts is the number of failed attempts to send the queued issue,
+	// that is not tried again before RetryAt. LastError is the error of
+	// the last one.
+	Attempts  int       `json:",omitempty"`
+	RetryAt   time.Time `json:",omitzero"`
+	LastError string    `json:",omitempty"`
+	// State is one of [IssuePending], [IssueQueued], [IssueSending] or
+	// [IssueSent].
+	State     string    `json:",omitempty"`
+	QueuedAt  time.Time `json:",omitzero"`
+	StartedAt time.Time `json:",omitzero"`
+	SentAt    time.Time `json:",omitzero"`
+}
+
+// Expired reports whether the issue has expired at the given time. Issues
+// that are queued or being sent do not expire.
+func (i *Issue) Expired(now time.Time) bool {
+	return i.State != IssueQueued && i.State != IssueSending && !now.Before(i.ExpiresAt)
+}
+
+// issueDir returns the directory of the issue with the given ID, or
+// [ErrNoIssue] if the ID is not a valid one.
+func (nl *Newsletter) issueDir(id string) (string, error) {
+	valid := id != "" && !strings.ContainsFunc(id, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= '2' && r <= '7' || r == '=')
+	})
+	if !valid {
+		return "", fmt.Errorf("%w with ID %q", ErrNoIssue, id)
+	}
+	return filepath.Join(nl.Config.Dir, IssuesDir, id), nil
+}
+
+// CreateIssue stores the given issue with its text body and, if not nil,
+// the raw submitted message. It expires after the confirmation expiry. It
+// returns [ErrIssueSent] if the same issue has already been sent.
+func (nl *Newsletter) CreateIssue(issue *Issue, body string, message []byte) error {
+	dir, err := nl.issueDir(issue.ID)
+	if err != nil {
+		return err
+	}
+	if current, err := nl.readIssue(issue.ID); err == nil && current.State != IssuePending {
+		return fmt.Errorf("%w: %s", ErrIssueSent, issue.ID)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create issue directory: %w", err)
+	}
+	issue.CreatedAt = nl.now().UTC().Truncate(time.Second)
+	issue.ExpiresAt = issue.CreatedAt.Add(nl.Config.Settings.ConfirmExpiryOrDefault())
+	issue.Approvals = nil
+	issue.Rich = message != nil
+	issue.State = IssuePending
+	if err := os.WriteFile(filepath.Join(dir, issueBodyFile), []byte(body), 0600); err != nil {
+		return fmt.Errorf("write issue body: %w", err)
+	}
+	if message != nil {
+		if err := os.WriteFile(filepath.Join(dir, issueMessageFile), message, 0600); err != nil {
+			return fmt.Errorf("write issue message: %w", err)
+		}
+	}
+	return nl.saveIssue(dir, issue)
+}
+
+func (nl *Newsletter) saveIssue(dir string, issue *Issue) error {
+	meta, err := json.MarshalIndent(issue, "", "\t")
+	if err != nil {
+		return fmt.Errorf("encode issue: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, issueMetaFile), append(meta, '\n'), 0600); err != nil {
+		return fmt.Errorf("write issue: %w", err)
+	}
+	return nil
+}
+
+func (nl *Newsletter) readIssue(id string) (*Issue, error) {
+	dir, err := nl.issueDir(id)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := os.ReadFile(filepath.Join(dir, issueMetaFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w with ID %s", ErrNoIssue, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read issue: %w", err)
+	}
+	issue := &Issue{ID: id}
+	if err := json.Unmarshal(meta, issue); err != nil {
+		return nil, fmt.Errorf("parse issue %s: %w", id, err)
+	}
+	return issue, nil
+}
+
+// Issue returns the pending issue with the given ID, or [ErrNoIssue] if
+// there is none or it has expired.
+func (nl *Newsletter) Issue(id string) (*Issue, error) {
+	issue, err := nl.readIssue(id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.Expired(nl.now()) {
+		return nil, fmt.Errorf("%w with ID %s: expired at %s", ErrNoIssue, id, issue.ExpiresAt)
+	}
+	return issue, nil
+}
+
+// IssueBody returns the text body of the given issue.
+func (nl *Newsletter) IssueBody(issue *Issue) (string, error) {
+	dir, err := nl.issueDir(issue.ID)
+	if err != nil {
+		return "", err
+	}
+	body, err := os.ReadFile(filepath.Join(dir, issueBodyFile))
+	if err != nil {
+		return "", fmt.Errorf("read issue body: %w", err)
+	}
+	return string(body), nil
+}
+
+// IssueMessage returns the raw submitted message of the given issue, or
+// nil if it has not been kept.
+func (nl *Newsletter) IssueMessage(issue *Issue) ([]byte, error) {
+	if !issue.Rich {
+		return nil, nil
+	}
+	dir, err := nl.issueDir(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	message, err := os.ReadFile(filepath.Join(dir, issueMessageFile))
+	if err != nil {
+		return nil, fmt.Errorf("read issue message: %w", err)
+	}
+	return message, nil
+}
+
+// ApproveIssue records the approval of the given issue by the given editor,
+// and returns all the editors that approved it.
+func (nl *Newsletter) ApproveIssue(issue *Issue, editor string) ([]string, error) {
+	if slices.ContainsFunc(issue.Approvals, func(a string) bool { return strings.EqualFold(a, editor) }) {
+		return issue.Approvals, nil
+	}
+	dir, err := nl.issueDir(issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue.Approvals = append(issue.Approvals, editor)
+	if err := nl.saveIssue(dir, issue); err != nil {
+		return nil, err
+	}
+	return issue.Approvals, nil
+}
+
+// Issues returns all the stored issues, including the expired ones that
+// have not been cleaned yet, in the order they were created.
+func (nl *Newsletter) Issues() ([]Issue, error) {
+	entries, err := os.ReadDir(filepath.Join(nl.Config.Dir, IssuesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read issues directory: %w", err)
+	}
+	var issues []Issue
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		issue, err := nl.readIssue(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, *issue)
+	}
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return issues, nil
+}
+
+// DiscardIssue removes the issue with the given ID, or returns
+// [ErrNoIssue] if there is none.
+func (nl *Newsletter) DiscardIssue(id string) error {
+	dir, err := nl.issueDir(id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w with ID %s", ErrNoIssue, id)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove issue: %w", err)
+	}
+	return nil
+}
+
+// CleanIssues removes the expired issues, and returns how many were
+// removed.
+func (nl *Newsletter) CleanIssues() (int, error) {
+	issues, err := nl.Issues()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	now := nl.now()
+	for _, issue := range issues {
+		if !issue.Expired(now) {
+			continue
+		}
+		if err := nl.DiscardIssue(issue.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// SendKeywordOrDefault returns [Settings.SendKeyword], or the localized
+// default one if it is not set.
+func (s *Settings) SendKeywordOrDefault() string {
+	if s.SendKeyword != "" {
+		return s.SendKeyword
+	}
+	return messages.Send_keyword.Print()
+}
+
+// CancelKeywordOrDefault returns [Settings.CancelKeyword], or the localized
+// default one if it is not set.
+func (s *Settings) CancelKeywordOrDefault() string {
+	if s.CancelKeyword != "" {
+		return s.CancelKeyword
+	}
+	return messages.Cancel_keyword.Print()
+}
+
+// ActionKeyword returns the keyword of the given action, as it must be
+// written in the replies.
+func (nl *Newsletter) ActionKeyword(action string) string {
+	switch action {
+	case ActionSend:
+		return nl.Config.Settings.SendKeywordOrDefault()
+	case ActionCancel:
+		return nl.Config.Settings.CancelKeywordOrDefault()
+	case ActionApprove:
+		return messages.Approve_keyword.Print()
+	case ActionReject:
+		return messages.Reject_keyword.Print()
+	}
+	return ""
+}
+
+// englishKeywords are the keywords of the actions that are always accepted.
+var englishKeywords = map[string]messages.Message{
+	ActionSend:    messages.Send_keyword,
+	ActionCancel:  messages.Cancel_keyword,
+	ActionApprove: messages.Approve_keyword,
+	ActionReject:  messages.Reject_keyword,
+}
+
+// ConfirmAction returns the action of the first line of the given reply
+// that only contains the keyword of one of the given actions, like
+// [ActionSend] or [ActionCancel], or an empty string if there is none.
+// Quoted lines and the signature are ignored, and the English keywords are
+// always accepted.
+func (nl *Newsletter) ConfirmAction(text string, actions ...string) string {
+	type actionKeyword struct{ keyword, action string }
+	var keywords []actionKeyword
+	for _, action := range actions {
+		keywords = append(keywords, actionKeyword{nl.ActionKeyword(action), action})
+	}
+	for _, action := range actions {
+		keywords = append(keywords, actionKeyword{englishKeywords[action].In(messages.LangEnglish), action})
+	}
+	for line := range strings.Lines(text) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "-- " {
+			break
+		}
+		if strings.HasPrefix(line, ">") {
+			continue
+		}
+		word := strings.TrimRight(strings.TrimSpace(line), ".!")
+		for _, k := range keywords {
+			if strings.EqualFold(word, k.keyword) {
+				return k.action
+			}
+		}
+	}
+	return ""
+}
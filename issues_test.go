@@ -0,0 +1,180 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/mailer/mailertest"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+func TestIssues(t *testing.T) {
+	nl := fakeNewsletter(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	nl.Now = func() time.Time { return now }
+
+	first := &newsletter.Issue{ID: "FIRST===", Subject: "First", Submitter: "user@club1.fr"}
+	if err := nl.CreateIssue(first, "First body", nil); err != nil {
+		t.Fatalf("create first issue: %v", err)
+	}
+	now = now.Add(time.Hour)
+	second := &newsletter.Issue{ID: "SECOND==", Subject: "Second", Submitter: "editor@club1.fr"}
+	if err := nl.CreateIssue(second, "Second body", []byte("Raw message")); err != nil {
+		t.Fatalf("create second issue: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(nl.Config.Dir, newsletter.IssuesDir, "SECOND=="))
+	if err != nil {
+		t.Fatalf("stat issue directory: %v", err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("expected private issue directory, got: %v", info.Mode())
+	}
+
+	if _, err := nl.ApproveIssue(second, "editor@club1.fr"); err != nil {
+		t.Fatalf("approve issue: %v", err)
+	}
+	approvals, err := nl.ApproveIssue(second, "Editor@club1.fr")
+	if err != nil || !reflect.DeepEqual(approvals, []string{"editor@club1.fr"}) {
+		t.Errorf("expected a single approval, got: %v (err: %v)", approvals, err)
+	}
+
+	expected := []newsletter.Issue{
+		{
+			ID:        "FIRST===",
+			Subject:   "First",
+			Submitter: "user@club1.fr",
+			CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			ExpiresAt: time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:        "SECOND==",
+			Subject:   "Second",
+			Submitter: "editor@club1.fr",
+			CreatedAt: time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC),
+			ExpiresAt: time.Date(2026, 10, 8, 13, 0, 0, 0, time.UTC),
+			Approvals: []string{"editor@club1.fr"},
+			Rich:      true,
+		},
+	}
+	issues, err := nl.Issues()
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("expected issues:\n%#v\ngot:\n%#v", expected, issues)
+	}
+
+	issue, err := nl.Issue("SECOND==")
+	if err != nil {
+		t.Fatalf("get issue: %v", err)
+	}
+	body, err := nl.IssueBody(issue)
+	if err != nil || body != "Second body" {
+		t.Errorf("expected body %q, got %q (err: %v)", "Second body", body, err)
+	}
+	message, err := nl.IssueMessage(issue)
+	if err != nil || string(message) != "Raw message" {
+		t.Errorf("expected message %q, got %q (err: %v)", "Raw message", message, err)
+	}
+	if message, err := nl.IssueMessage(&issues[0]); err != nil || message != nil {
+		t.Errorf("expected no message, got %q (err: %v)", message, err)
+	}
+
+	if err := nl.QueueIssue(issue, &mailer.Mail{}); err != nil {
+		t.Fatalf("queue issue: %v", err)
+	}
+	if err := nl.QueueIssue(&issues[1], &mailer.Mail{}); !errors.Is(err, newsletter.ErrIssueSent) {
+		t.Errorf("expected error %v when queuing twice, got: %v", newsletter.ErrIssueSent, err)
+	}
+	if issues[1].State != newsletter.IssueQueued {
+		t.Errorf("expected issue to be updated to state %q, got %q", newsletter.IssueQueued, issues[1].State)
+	}
+	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error { return nil }}
+	for _, err := range nl.RunQueue() {
+		if err != nil {
+			t.Fatalf("run queue: %v", err)
+		}
+	}
+	if err := nl.CreateIssue(second, "Second body", nil); !errors.Is(err, newsletter.ErrIssueSent) {
+		t.Errorf("expected error %v when creating a sent issue, got: %v", newsletter.ErrIssueSent, err)
+	}
+	if issue, err := nl.Issue("SECOND=="); err != nil || issue.State != newsletter.IssueSent || !issue.SentAt.Equal(now) {
+		t.Errorf("expected issue sent at %v, got: %#v (err: %v)", now, issue, err)
+	}
+
+	now = time.Date(2026, 10, 8, 12, 30, 0, 0, time.UTC)
+	if _, err := nl.Issue("FIRST==="); !errors.Is(err, newsletter.ErrNoIssue) {
+		t.Errorf("expected error %v for an expired issue, got: %v", newsletter.ErrNoIssue, err)
+	}
+	count, err := nl.CleanIssues()
+	if err != nil || count != 1 {
+		t.Errorf("expected 1 cleaned issue, got %v (err: %v)", count, err)
+	}
+	if err := nl.DiscardIssue("SECOND=="); err != nil {
+		t.Errorf("discard issue: %v", err)
+	}
+	if issues, err := nl.Issues(); err != nil || len(issues) != 0 {
+		t.Errorf("expected no issues, got: %v (err: %v)", issues, err)
+	}
+
+	for _, id := range []string{"SECOND==", "../FIRST", ""} {
+		if err := nl.DiscardIssue(id); !errors.Is(err, newsletter.ErrNoIssue) {
+			t.Errorf("discard %q: expected error %v, got: %v", id, newsletter.ErrNoIssue, err)
+		}
+	}
+}
+
+func TestConfirmAction(t *testing.T) {
+	nl := fakeNewsletter(t)
+	messages.SetLanguage(messages.LangFrench)
+	t.Cleanup(func() { messages.SetLanguage("") })
+	cases := []struct {
+		name     string
+		cancel   string
+		text     string
+		expected string
+	}{
+		{"localized", "", "Envoyer\n", newsletter.ActionSend},
+		{"english", "", "ok\r\ncancel.\r\n", newsletter.ActionCancel},
+		{"first keyword", "", "SEND\nCANCEL\n", newsletter.ActionSend},
+		{"quoted", "", "> ENVOYER\nMerci\n", ""},
+		{"signature", "", "Merci\n-- \nENVOYER\n", ""},
+		{"not alone", "", "Please send it\n", ""},
+		{"custom", "stop", "Stop!\n", newsletter.ActionCancel},
+		{"custom replaces localized", "stop", "annuler\n", ""},
+		{"other action", "", "Approuver\nEnvoyer\n", newsletter.ActionSend},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			nl.Config.Settings.CancelKeyword = c.cancel
+			if action := nl.ConfirmAction(c.text, newsletter.ActionSend, newsletter.ActionCancel); action != c.expected {
+				t.Errorf("expected action %q, got %q", c.expected, action)
+			}
+		})
+	}
+}
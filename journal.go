@@ -0,0 +1,133 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Events of the journal that are not routes.
+const (
+	EventAdd        = "add"
+	EventRemove     = "remove"
+	EventImport     = "import"
+	EventConfig     = "config"
+	EventBounce     = "bounce"
+	EventUnsuppress = "unsuppress"
+)
+
+// Outcomes of the journal entries.
+const (
+	OutcomeOK       = "ok"
+	OutcomeRejected = "rejected"
+	OutcomeError    = "error"
+)
+
+// JournalEntry is an event of the list recorded in the journal. The events
+// triggered by a mail are named after their route, with the topic routes
+// recorded as [RouteSubscribe].
+type JournalEntry struct {
+	Time      time.Time
+	Event     string
+	Route     string `json:",omitempty"`
+	Address   string `json:",omitempty"`
+	MessageID string `json:",omitempty"`
+	Outcome   string
+	Detail    string `json:",omitempty"`
+}
+
+// JournalFilter selects journal entries. Zero fields match any entry.
+type JournalFilter struct {
+	Since   time.Time
+	Until   time.Time
+	Address string
+	Event   string
+}
+
+// Match reports whether the entry is selected by the filter.
+func (f *JournalFilter) Match(e *JournalEntry) bool {
+	return (f.Since.IsZero() || !e.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || e.Time.Before(f.Until)) &&
+		(f.Address == "" || strings.EqualFold(e.Address, f.Address)) &&
+		(f.Event == "" || e.Event == f.Event)
+}
+
+// Record appends the given entry to the journal, setting its time to the
+// current time if it is not set.
+func (nl *Newsletter) Record(e JournalEntry) error {
+	if e.Time.IsZero() {
+		e.Time = nl.now().UTC().Truncate(time.Second)
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode journal entry: %w", err)
+	}
+	path := filepath.Join(nl.Config.Dir, JournalFile)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	defer file.Close()
+	// a single write keeps concurrent appends from being interleaved
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+	return nil
+}
+
+// Journal returns the entries of the journal selected by the filter, in
+// the order they were recorded. The lines that cannot be decoded are
+// skipped.
+func (nl *Newsletter) Journal(filter JournalFilter) ([]JournalEntry, error) {
+	file, err := os.Open(filepath.Join(nl.Config.Dir, JournalFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// a line may be truncated if the process was interrupted
+			// or the disk was full while recording it
+			continue
+		}
+		if filter.Match(&e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
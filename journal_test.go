@@ -0,0 +1,84 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestJournal(t *testing.T) {
+	nl := fakeNewsletter(t)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	entries := []newsletter.JournalEntry{
+		{Time: day, Event: newsletter.RouteSubscribe, Route: "subscribe-events", Address: "a@club1.fr", MessageID: "<id1@club1.fr>", Outcome: newsletter.OutcomeOK, Detail: "topics: events"},
+		{Time: day.Add(time.Hour), Event: newsletter.RouteSubscribeConfirm, Route: "subscribe-confirm", Address: "a@club1.fr", MessageID: "<id2@club1.fr>", Outcome: newsletter.OutcomeError, Detail: "expired token"},
+		{Time: day.Add(24 * time.Hour), Event: newsletter.EventRemove, Address: "b@club1.fr", Outcome: newsletter.OutcomeOK},
+	}
+	for _, e := range entries {
+		if err := nl.Record(e); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	// a truncated line, as left by an interrupted write
+	journal, err := os.OpenFile(filepath.Join(nl.Config.Dir, newsletter.JournalFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal.WriteString(`{"Time":"2026-10-0` + "\n")
+	journal.Close()
+	nl.Now = func() time.Time { return day.Add(48 * time.Hour) }
+	if err := nl.Record(newsletter.JournalEntry{Event: newsletter.EventConfig, Outcome: newsletter.OutcomeOK}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		filter   newsletter.JournalFilter
+		expected []int
+	}{
+		{"all", newsletter.JournalFilter{}, []int{0, 1, 2, 3}},
+		{"address", newsletter.JournalFilter{Address: "A@club1.fr"}, []int{0, 1}},
+		{"event", newsletter.JournalFilter{Event: newsletter.EventRemove}, []int{2}},
+		{"since", newsletter.JournalFilter{Since: day.Add(time.Hour)}, []int{1, 2, 3}},
+		{"until", newsletter.JournalFilter{Until: day.Add(time.Hour)}, []int{0}},
+	}
+	all := append(entries, newsletter.JournalEntry{Time: day.Add(48 * time.Hour), Event: newsletter.EventConfig, Outcome: newsletter.OutcomeOK})
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := nl.Journal(c.filter)
+			if err != nil {
+				t.Fatalf("journal: %v", err)
+			}
+			var expected []newsletter.JournalEntry
+			for _, i := range c.expected {
+				expected = append(expected, all[i])
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected entries:\n%#v\ngot:\n%#v", expected, actual)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@
 
 package mailer
 
+import "fmt"
+
 type Mail struct {
 	From            string
 	To              string
@@ -30,18 +32,43 @@ type Mail struct {
 	ListUnsubscribe string
 	Subject         string
 	Body            string
+	// HTML is an alternative HTML version of the body, sent along with it
+	// if not empty.
+	HTML        string
+	Attachments []Attachment
 }
 
 type Mailer interface {
 	Send(m *Mail) error
 }
 
+// Mailer backends.
+const (
+	BackendMailx    = "mailx"
+	BackendSendmail = "sendmail"
+)
+
 var defaultMailer Mailer = &mailxMailer{}
 
 func Default() Mailer {
 	return defaultMailer
 }
 
+// New returns the [Mailer] of the given backend, or the default one if
+// backend is empty.
+func New(backend string) (Mailer, error) {
+	switch backend {
+	case "":
+		return defaultMailer, nil
+	case BackendMailx:
+		return &mailxMailer{}, nil
+	case BackendSendmail:
+		return &sendmailMailer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown mailer backend %q", backend)
+	}
+}
+
 // Send sends a mail using the default [Mailer].
 //
 // Deprecated: use [Default()] to get a usable [Mailer] instead.
@@ -47,22 +47,19 @@ func (m *mailxMailer) Send(mail *Mail) error {
 		return fmt.Errorf("no recipient address found")
 	}
 
-	encodedBody, err := quotedPrintable(mail.Body)
+	contentHeaders, encodedBody, err := formatContent(mail)
 	if err != nil {
-		return fmt.Errorf("encode body: %w", err)
+		return err
 	}
 
 	args := []string{
 		"-s", mail.Subject,
 		"-r", mail.From,
-		"-a", "Content-Transfer-Encoding: quoted-printable",
-		"-a", "Content-Type: text/plain; charset=UTF-8",
 	}
-	type Header struct {
-		name  string
-		value string
+	for _, header := range contentHeaders {
+		args = append(args, "-a", header.name+": "+header.value)
 	}
-	headers := []Header{
+	headers := []header{
 		{"Message-Id", mail.Id},
 		{"In-Reply-To", mail.InReplyTo},
 		{"References", mail.References},
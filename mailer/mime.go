@@ -0,0 +1,159 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// Attachment is a file attached to a [Mail].
+type Attachment struct {
+	Filename    string
+	ContentType string
+	// ContentID identifies the inline files referenced by the HTML body,
+	// it is empty for the other attachments.
+	ContentID string
+	Data      []byte
+}
+
+type header struct {
+	name  string
+	value string
+}
+
+// part is a MIME entity, with its header fields and its encoded body.
+type part struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+func textPart(contentType string, s string) (part, error) {
+	encoded, err := quotedPrintable(s)
+	if err != nil {
+		return part{}, err
+	}
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", contentType+"; charset=UTF-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	return part{h, encoded.Bytes()}, nil
+}
+
+func attachmentPart(a *Attachment) part {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := "attachment"
+	h := textproto.MIMEHeader{}
+	if a.ContentID != "" {
+		disposition = "inline"
+		h.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	if a.Filename != "" {
+		contentType = mime.FormatMediaType(contentType, map[string]string{"name": a.Filename})
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename})
+	}
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Disposition", disposition)
+	h.Set("Content-Transfer-Encoding", "base64")
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	var body bytes.Buffer
+	for len(encoded) > 76 {
+		body.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	body.WriteString(encoded)
+	return part{h, body.Bytes()}
+}
+
+func multipartPart(subtype string, parts []part) (part, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		pw, err := w.CreatePart(p.header)
+		if err != nil {
+			return part{}, err
+		}
+		if _, err := pw.Write(p.body); err != nil {
+			return part{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return part{}, err
+	}
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": w.Boundary()}))
+	return part{h, buf.Bytes()}, nil
+}
+
+// formatContent encodes the body, the HTML body and the attachments of the
+// mail, and returns the header fields describing the encoded content.
+//
+// Mails with only a text body are sent as text/plain, the others as
+// multipart/mixed for the attachments, containing multipart/alternative for
+// the text and HTML bodies, the HTML body being in multipart/related with
+// the inline files it references.
+func formatContent(m *Mail) ([]header, *bytes.Buffer, error) {
+	content, err := textPart("text/plain", m.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode body: %w", err)
+	}
+
+	var inline, attached []part
+	for i := range m.Attachments {
+		if m.Attachments[i].ContentID != "" && m.HTML != "" {
+			inline = append(inline, attachmentPart(&m.Attachments[i]))
+		} else {
+			attached = append(attached, attachmentPart(&m.Attachments[i]))
+		}
+	}
+
+	if m.HTML != "" {
+		html, err := textPart("text/html", m.HTML)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode HTML body: %w", err)
+		}
+		if len(inline) > 0 {
+			if html, err = multipartPart("related", append([]part{html}, inline...)); err != nil {
+				return nil, nil, err
+			}
+		}
+		if content, err = multipartPart("alternative", []part{content, html}); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(attached) > 0 {
+		if content, err = multipartPart("mixed", append([]part{content}, attached...)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	headers := []header{{"Content-Type", content.header.Get("Content-Type")}}
+	if cte := content.header.Get("Content-Transfer-Encoding"); cte != "" {
+		headers = append(headers, header{"Content-Transfer-Encoding", cte})
+	}
+	return headers, bytes.NewBuffer(content.body), nil
+}
@@ -0,0 +1,143 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mimeTree returns the content types of the tree of MIME parts of r, with
+// the decoded content of the leaves.
+func mimeTree(t *testing.T, contentType string, r io.Reader) []string {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("parse content type %q: %v", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		return []string{mediaType + ": " + string(content)}
+	}
+	tree := []string{mediaType}
+	reader := multipart.NewReader(r, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		var body io.Reader = p
+		if p.Header.Get("Content-Transfer-Encoding") == "base64" {
+			content, _ := io.ReadAll(p)
+			body = bytes.NewReader(decodeBase64(t, string(content)))
+		}
+		for _, line := range mimeTree(t, p.Header.Get("Content-Type"), body) {
+			tree = append(tree, "  "+line)
+		}
+	}
+	return tree
+}
+
+func decodeBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	content, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, strings.NewReader(s)))
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	return content
+}
+
+func TestFormatMessageMultipart(t *testing.T) {
+	cases := []struct {
+		name     string
+		mail     *Mail
+		expected []string
+	}{
+		{
+			"html",
+			&Mail{Body: "Texte", HTML: "<p>Texte</p>"},
+			[]string{
+				"multipart/alternative",
+				"  text/plain: Texte",
+				"  text/html: <p>Texte</p>",
+			},
+		},
+		{
+			"attachments",
+			&Mail{
+				Body: "Texte",
+				HTML: `<p><img src="cid:logo@club1.fr"></p>`,
+				Attachments: []Attachment{
+					{Filename: "logo.png", ContentType: "image/png", ContentID: "logo@club1.fr", Data: []byte("PNG")},
+					{Filename: "programme été.pdf", ContentType: "application/pdf", Data: []byte(strings.Repeat("PDF", 50))},
+				},
+			},
+			[]string{
+				"multipart/mixed",
+				"  multipart/alternative",
+				"    text/plain: Texte",
+				"    multipart/related",
+				`      text/html: <p><img src="cid:logo@club1.fr"></p>`,
+				"      image/png: PNG",
+				"  application/pdf: " + strings.Repeat("PDF", 50),
+			},
+		},
+		{
+			"text attachment",
+			&Mail{Body: "Texte", Attachments: []Attachment{{Filename: "notes.txt", Data: []byte("notes")}}},
+			[]string{
+				"multipart/mixed",
+				"  text/plain: Texte",
+				"  application/octet-stream: notes",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.mail.From = "nouvelles@club1.fr"
+			c.mail.To = "test@gmail.com"
+			message, err := formatMessage(c.mail)
+			if err != nil {
+				t.Fatalf("format message: %v", err)
+			}
+			parsed, err := mail.ReadMessage(message)
+			if err != nil {
+				t.Fatalf("read message: %v", err)
+			}
+			tree := mimeTree(t, parsed.Header.Get("Content-Type"), parsed.Body)
+			if !reflect.DeepEqual(tree, c.expected) {
+				t.Errorf("expected MIME tree:\n%s\ngot:\n%s", strings.Join(c.expected, "\n"), strings.Join(tree, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"os/exec"
+	"time"
+)
+
+type sendmailMailer struct{}
+
+func (m *sendmailMailer) Send(mail *Mail) error {
+	if mail.To == "" {
+		return fmt.Errorf("no recipient address found")
+	}
+	message, err := formatMessage(mail)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-i"}
+	if from, err := parseAddress(mail.From); err == nil {
+		args = append(args, "-f", from)
+	}
+	args = append(args, "--", mail.To)
+
+	cmd := exec.Command("sendmail", args...)
+	cmd.Stdin = message
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("execute command: %w: %s", err, out)
+	}
+	return nil
+}
+
+func parseAddress(s string) (string, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return "", err
+	}
+	return addr.Address, nil
+}
+
+// formatMessage formats the mail as an RFC 5322 message.
+func formatMessage(m *Mail) (*bytes.Buffer, error) {
+	contentHeaders, encodedBody, err := formatContent(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	headers := []header{
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"From", m.From},
+		{"To", m.To},
+		{"Subject", mime.QEncoding.Encode("UTF-8", m.Subject)},
+		{"Message-Id", m.Id},
+		{"In-Reply-To", m.InReplyTo},
+		{"References", m.References},
+		{"Reply-To", m.ReplyTo},
+		{"List-Id", m.ListId},
+		{"List-Unsubscribe", m.ListUnsubscribe},
+		{"MIME-Version", "1.0"},
+	}
+	headers = append(headers, contentHeaders...)
+	for _, header := range headers {
+		if header.value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", header.name, header.value)
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(encodedBody.Bytes())
+	return &buf, nil
+}
@@ -0,0 +1,88 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSendmail(t *testing.T) {
+	tmp := t.TempDir()
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmdPath := filepath.Join(tmp, "sendmail_cmd")
+	stdinPath := filepath.Join(tmp, "sendmail_stdin")
+	t.Setenv("PATH", filepath.Join(testdata, "bin"))
+	t.Setenv("SENDMAIL_CMD", cmdPath)
+	t.Setenv("SENDMAIL_STDIN", stdinPath)
+
+	mailer, err := New(BackendSendmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mailer.Send(&Mail{
+		From:      "Nouvelles de CLUB1 <nouvelles@club1.fr>",
+		To:        "test@gmail.com",
+		Subject:   "Ça dit quoi ?",
+		InReplyTo: "<test-id@club1.fr>",
+		Body:      "Coucou, ça dit quoi ?",
+	})
+	if err != nil {
+		t.Errorf("send: %v", err)
+	}
+
+	cmd, err := os.ReadFile(cmdPath)
+	if err != nil {
+		t.Fatalf("read sendmail cmd: %v", err)
+	}
+	if expected := "sendmail -i -f nouvelles@club1.fr -- test@gmail.com"; string(cmd) != expected {
+		t.Errorf("expected command:\n%s\ngot:\n%s", expected, cmd)
+	}
+
+	stdin, err := os.ReadFile(stdinPath)
+	if err != nil {
+		t.Fatalf("read sendmail stdin: %v", err)
+	}
+	message := regexp.MustCompile(`^Date: .*\r\n`).ReplaceAllString(string(stdin), "")
+	expected := strings.Join([]string{
+		"From: Nouvelles de CLUB1 <nouvelles@club1.fr>",
+		"To: test@gmail.com",
+		"Subject: =?UTF-8?q?=C3=87a_dit_quoi_=3F?=",
+		"In-Reply-To: <test-id@club1.fr>",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"Coucou, =C3=A7a dit quoi ?",
+	}, "\r\n")
+	if message != expected {
+		t.Errorf("expected message:\n%q\ngot:\n%q", expected, message)
+	}
+
+	if _, err := New("pigeon"); err == nil {
+		t.Errorf("expected error for unknown backend")
+	}
+}
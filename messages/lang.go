@@ -38,7 +38,13 @@ type Message struct {
 }
 
 func (m Message) Print() string {
-	switch language {
+	return m.In(language)
+}
+
+// In returns the message in the given language, in English if it is
+// unknown.
+func (m Message) In(l Language) string {
+	switch l {
 	case LangEnglish:
 		return m.en
 	case LangFrench:
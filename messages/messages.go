@@ -84,8 +84,250 @@ var (
 		en: "Your email cannot be added to the subscripted list, contact list owner for more info: <%s>.",
 		fr: "Votre email ne peut pas être inscrit à la liste, veuillez contacter le propriétaire de la liste pour plus d'infos : <%s>.",
 	}
+	ConfirmationExpired_subject = Message{
+		en: "Confirmation expired",
+		fr: "Confirmation expirée",
+	}
+	ConfirmationExpired_body = Message{
+		en: "Your subscription request has expired, send a new mail to <%s> to subscribe again.",
+		fr: "Votre demande d'inscription a expiré, envoyez un nouvel email à <%s> pour vous inscrire à nouveau.",
+	}
 	Newsletter_footer = Message{
 		en: "\n\nTo unsubscribe, send a mail to <%s>",
 		fr: "\n\nPour vous désinscrire, envoyez un email à <%s>",
 	}
+	NewsletterTopic_footer = Message{
+		en: "\nYou receive this mail because you subscribed to the topic: %s",
+		fr: "\nVous recevez cet email car vous êtes inscrit au thème : %s",
+	}
+	Topics_line = Message{
+		en: "\n\nTopics: %s",
+		fr: "\n\nThèmes : %s",
+	}
+	SubscriptionPending_subject = Message{
+		en: "Subscription awaiting approval",
+		fr: "Inscription en attente de validation",
+	}
+	SubscriptionPending_body = Message{
+		en: "Your subscription to the newsletter [%s] is confirmed, and now awaits the approval of its owner.",
+		fr: "Votre inscription à la newsletter [%s] est confirmée, et attend maintenant la validation de son propriétaire.",
+	}
+	SubscriptionPendingAlt_body = Message{
+		en: "Your subscription to %s's newsletter is confirmed, and now awaits the approval of its owner.",
+		fr: "Votre inscription à la newsletter de %s est confirmée, et attend maintenant la validation de son propriétaire.",
+	}
+	ApprovalRequest_subject = Message{
+		en: "Subscription to approve: %s",
+		fr: "Inscription à valider : %s",
+	}
+	ApprovalRequest_body = Message{
+		en: "%s confirmed their subscription request.",
+		fr: "%s a confirmé sa demande d'inscription.",
+	}
+	ApprovalRequestReply_line = Message{
+		en: "\n\nReply to this email with a line containing only %s to approve it, or %s to reject it. It can also be done with the `newsletter pending approve|reject ADDRESS` command.",
+		fr: "\n\nRépondez à cet email avec une ligne contenant seulement %s pour la valider, ou %s pour la refuser. Cela peut aussi être fait avec la commande `newsletter pending approve|reject ADRESSE`.",
+	}
+	ApprovalNoKeyword_subject = Message{
+		en: "Subscription not moderated",
+		fr: "Inscription non modérée",
+	}
+	ApprovalNoKeyword_body = Message{
+		en: "Your reply does not contain a line with only %[1]s or %[2]s, so nothing has been done. To approve the subscription of <%[3]s>, reply to its request with the line %[1]s, or with %[2]s to reject it.",
+		fr: "Votre réponse ne contient pas de ligne avec seulement %[1]s ou %[2]s, rien n'a donc été fait. Pour valider l'inscription de <%[3]s>, répondez à sa demande avec la ligne %[1]s, ou avec %[2]s pour la refuser.",
+	}
+	SubscriptionRejected_subject = Message{
+		en: "Subscription rejected",
+		fr: "Inscription refusée",
+	}
+	SubscriptionRejected_body = Message{
+		en: "Your subscription to the newsletter [%s] has been rejected by its owner.",
+		fr: "Votre inscription à la newsletter [%s] a été refusée par son propriétaire.",
+	}
+	SubscriptionRejectedAlt_body = Message{
+		en: "Your subscription to %s's newsletter has been rejected by its owner.",
+		fr: "Votre inscription à la newsletter de %s a été refusée par son propriétaire.",
+	}
+	IssueAlreadySent_subject = Message{
+		en: "Newsletter already sent",
+		fr: "Newsletter déjà envoyée",
+	}
+	IssueAlreadySent_body = Message{
+		en: "The newsletter \"%s\" has already been sent, or is being sent, so this confirmation from <%s> has been ignored.",
+		fr: "La newsletter « %s » a déjà été envoyée, ou est en cours d'envoi, cette confirmation de <%s> a donc été ignorée.",
+	}
+	Send_keyword = Message{
+		en: "SEND",
+		fr: "ENVOYER",
+	}
+	Cancel_keyword = Message{
+		en: "CANCEL",
+		fr: "ANNULER",
+	}
+	Approve_keyword = Message{
+		en: "APPROVE",
+		fr: "APPROUVER",
+	}
+	Reject_keyword = Message{
+		en: "REJECT",
+		fr: "REFUSER",
+	}
+	IssueNoKeyword_subject = Message{
+		en: "Newsletter not sent",
+		fr: "Newsletter non envoyée",
+	}
+	IssueNoKeyword_body = Message{
+		en: "Your reply does not contain a line with only %[1]s or %[2]s, so nothing has been done. To send the newsletter \"%[3]s\", reply to its preview with the line %[1]s, or with %[2]s to cancel it.",
+		fr: "Votre réponse ne contient pas de ligne avec seulement %[1]s ou %[2]s, rien n'a donc été fait. Pour envoyer la newsletter « %[3]s », répondez à son aperçu avec la ligne %[1]s, ou avec %[2]s pour l'annuler.",
+	}
+	IssueCancelled_subject = Message{
+		en: "Newsletter cancelled",
+		fr: "Newsletter annulée",
+	}
+	IssueCancelled_body = Message{
+		en: "The newsletter \"%s\" has been cancelled, it will not be sent.",
+		fr: "La newsletter « %s » a été annulée, elle ne sera pas envoyée.",
+	}
+	IssueApproved_subject = Message{
+		en: "Newsletter approved",
+		fr: "Newsletter approuvée",
+	}
+	IssueApproved_body = Message{
+		en: "Your approval of the newsletter \"%s\" has been recorded, %v more approvals are needed to send it.",
+		fr: "Votre approbation de la newsletter « %s » a été enregistrée, %v approbations de plus sont nécessaires pour l'envoyer.",
+	}
+	IssueQueued_subject = Message{
+		en: "Newsletter confirmed",
+		fr: "Newsletter confirmée",
+	}
+	IssueQueued_body = Message{
+		en: "The newsletter \"%s\" has been confirmed, and is being sent to %v subscribers.",
+		fr: "La newsletter « %s » a été confirmée, et est en cours d'envoi à %v abonnés.",
+	}
+	IssueScheduled_body = Message{
+		en: "The newsletter \"%s\" has been confirmed, and will be sent to %v subscribers on %s.",
+		fr: "La newsletter « %s » a été confirmée, et sera envoyée à %v abonnés le %s.",
+	}
+	IssueReport_subject = Message{
+		en: "Newsletter sent: %s",
+		fr: "Newsletter envoyée : %s",
+	}
+	IssueReport_body = Message{
+		en: "The newsletter \"%s\" (issue %s) has been sent to %v subscribers in %v.",
+		fr: "La newsletter « %s » (numéro %s) a été envoyée à %v abonnés en %v.",
+	}
+	IssueReportFailures_line = Message{
+		en: "\n\n%v of the deliveries failed:",
+		fr: "\n\n%v des envois ont échoué :",
+	}
+	IssueReportError_subject = Message{
+		en: "Newsletter sending interrupted: %s",
+		fr: "Envoi de la newsletter interrompu : %s",
+	}
+	IssueReportError_body = Message{
+		en: "The sending of the newsletter \"%s\" (issue %s) has been interrupted after %v deliveries, because of the error:\n%v\n\nIt will be resumed after %s. You will not be notified again if it fails with the same error.",
+		fr: "L'envoi de la newsletter « %s » (numéro %s) a été interrompu après %v envois, à cause de l'erreur :\n%v\n\nIl reprendra après %s. Vous ne serez pas prévenu à nouveau s'il échoue avec la même erreur.",
+	}
+	RequestRejected_subject = Message{
+		en: "Newsletter request rejected",
+		fr: "Demande de newsletter refusée",
+	}
+	RequestRejected_body = Message{
+		en: "A mail from <%s> to the %s address has been rejected:\n%s",
+		fr: "Un email de <%s> à l'adresse %s a été refusé :\n%s",
+	}
+	AdminReport_subject = Message{
+		en: "Admin report",
+		fr: "Rapport d'administration",
+	}
+	AdminHelp_body = Message{
+		en: `Available commands, one per line:
+
+    list                 list the subscribers
+    count                count the subscribers
+    add ADDRESS          add a subscriber
+    remove ADDRESS       remove a subscriber
+    stats                show statistics about the list
+    settings title TEXT  change the title of the newsletter
+
+The add, remove and settings commands are only run once confirmed by a
+reply to the report.
+`,
+		fr: `Commandes disponibles, une par ligne :
+
+    list                  lister les abonnés
+    count                 compter les abonnés
+    add ADRESSE           ajouter un abonné
+    remove ADRESSE        retirer un abonné
+    stats                 afficher des statistiques sur la liste
+    settings title TEXTE  changer le titre de la newsletter
+
+Les commandes add, remove et settings ne sont exécutées qu'une fois
+confirmées par une réponse au rapport.
+`,
+	}
+	AdminError_line = Message{
+		en: "error: %v\n\n",
+		fr: "erreur : %v\n\n",
+	}
+	AdminNoSubscribers_line = Message{
+		en: "no subscribers\n",
+		fr: "aucun abonné\n",
+	}
+	AdminCount_line = Message{
+		en: "%v subscribers\n",
+		fr: "%v abonnés\n",
+	}
+	AdminAdded_line = Message{
+		en: "address added: %s\n",
+		fr: "adresse ajoutée : %s\n",
+	}
+	AdminRemoved_line = Message{
+		en: "address removed: %s\n",
+		fr: "adresse retirée : %s\n",
+	}
+	AdminSettingSet_line = Message{
+		en: "%s set to %q\n",
+		fr: "%s changé en %q\n",
+	}
+	AdminStatsSubscribers_line = Message{
+		en: "subscribers: %v\n",
+		fr: "abonnés : %v\n",
+	}
+	AdminStatsTopic_line = Message{
+		en: "subscribers of topic %s: %v\n",
+		fr: "abonnés du thème %s : %v\n",
+	}
+	AdminStatsSuppressed_line = Message{
+		en: "suppressed addresses: %v\n",
+		fr: "adresses exclues : %v\n",
+	}
+	AdminStatsPeriod_line = Message{
+		en: "in the last %v days:\n",
+		fr: "ces %v derniers jours :\n",
+	}
+	AdminConfirm_subject = Message{
+		en: "Admin commands to confirm",
+		fr: "Commandes d'administration à confirmer",
+	}
+	AdminConfirm_line = Message{
+		en: "\nThese commands change the list. Reply to this email with a line\ncontaining only %s to run them, or %s to drop them.",
+		fr: "\nCes commandes modifient la liste. Répondez à cet email avec une ligne\ncontenant seulement %s pour les exécuter, ou %s pour les abandonner.",
+	}
+	AdminNoKeyword_subject = Message{
+		en: "Admin commands not confirmed",
+		fr: "Commandes d'administration non confirmées",
+	}
+	AdminNoKeyword_body = Message{
+		en: "No line containing only %s or %s was found in your reply,\nthe commands have not been run.",
+		fr: "Votre réponse ne contient pas de ligne avec seulement %s ou %s,\nles commandes n'ont pas été exécutées.",
+	}
+	AdminCancelled_subject = Message{
+		en: "Admin commands cancelled",
+		fr: "Commandes d'administration annulées",
+	}
+	AdminCancelled_body = Message{
+		en: "The commands have been dropped.",
+		fr: "Les commandes ont été abandonnées.",
+	}
 )
@@ -20,42 +20,127 @@
 package newsletter
 
 import (
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
 	"fmt"
 	"iter"
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/messages"
 )
 
 const (
 	ConfigPath = ".config/newsletter"
+	// ConfigDirEnv is the environment variable that overrides the config
+	// directory of the default list.
+	ConfigDirEnv = "NEWSLETTER_CONFIG_DIR"
 
 	RouteSubscribe        = "subscribe"
 	RouteSubscribeConfirm = "subscribe-confirm"
 	RouteUnSubscribe      = "unsubscribe"
 	RouteSend             = "send"
 	RouteSendConfirm      = "send-confirm"
+	RouteAdmin            = "admin"
+	RouteModerate         = "moderate"
 )
 
 var (
-	Routes = [...]string{RouteSubscribe, RouteSubscribeConfirm, RouteUnSubscribe, RouteSend, RouteSendConfirm}
+	Routes = [...]string{RouteSubscribe, RouteSubscribeConfirm, RouteUnSubscribe, RouteSend, RouteSendConfirm, RouteAdmin, RouteModerate}
+
+	listNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
 )
 
 type Newsletter struct {
 	Config    *Config
 	Hostname  string
 	LocalUser string
-	Mailer    mailer.Mailer
+	// List is the name of the list, empty for the default one.
+	List   string
+	Mailer mailer.Mailer
+	// Now returns the current time, defaults to [time.Now] if nil.
+	Now func() time.Time
 }
 
-// New creates a new [Newsletter] instance and initialises it.
+// New creates a new [Newsletter] instance for the default list and
+// initialises it.
 //
 // It reads information about the system, the current user and its config
 // directory, then loads the config from the filesystem.
 func New() (*Newsletter, error) {
+	return NewList("")
+}
+
+// ValidateListName checks that the given name can be used as a list name.
+func ValidateListName(name string) error {
+	if !listNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid list name %q: must only contain lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// DefaultConfigDir returns the config directory of the default list when
+// it is not overridden, `~/.config/newsletter`.
+func DefaultConfigDir() (string, error) {
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		user, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("get local user: %w", err)
+		}
+		homeDir = user.HomeDir
+	}
+	return filepath.Join(homeDir, ConfigPath), nil
+}
+
+// BaseConfigDir returns the config directory of the default list, which is
+// the value of [ConfigDirEnv] if set, `$XDG_CONFIG_HOME/newsletter` if
+// XDG_CONFIG_HOME is set, or [DefaultConfigDir] otherwise.
+func BaseConfigDir() (string, error) {
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "newsletter"), nil
+	}
+	return DefaultConfigDir()
+}
+
+// ConfigDir returns the config directory of the list with the given name,
+// or of the default list if name is empty. The lists are stored in the
+// given base directory, or in [BaseConfigDir] if base is empty.
+func ConfigDir(base string, name string) (string, error) {
+	if base == "" {
+		var err error
+		if base, err = BaseConfigDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(base, name), nil
+}
+
+// NewList is like [New] but for the list with the given name, whose config
+// is stored in a subdirectory of the default list's config directory.
+// An empty name selects the default list.
+func NewList(name string) (*Newsletter, error) {
+	return NewListIn("", name)
+}
+
+// NewListIn is like [NewList], but the config directory of the default
+// list is the given base directory instead of [BaseConfigDir], if not empty.
+func NewListIn(base string, name string) (*Newsletter, error) {
+	if name != "" {
+		if err := ValidateListName(name); err != nil {
+			return nil, err
+		}
+	}
+
 	hostname, err := os.Hostname()
 	if err != nil {
 		return nil, fmt.Errorf("get hostname: %w", err)
@@ -66,30 +151,76 @@ func New() (*Newsletter, error) {
 		return nil, fmt.Errorf("get local user: %w", err)
 	}
 
-	homeDir := os.Getenv("HOME")
-	if homeDir == "" {
-		homeDir = user.HomeDir
+	configDir, err := ConfigDir(base, name)
+	if err != nil {
+		return nil, err
 	}
-
-	config, err := InitConfig(filepath.Join(homeDir, ConfigPath))
+	config, err := InitConfig(configDir)
 	if err != nil {
 		return nil, fmt.Errorf("init config: %w", err)
 	}
 
+	if config.System.Defaults.Hostname != "" {
+		hostname = config.System.Defaults.Hostname
+	}
+	m, err := mailer.New(config.System.Defaults.Mailer)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Newsletter{
 		Config:    config,
 		Hostname:  hostname,
 		LocalUser: user.Username,
-		Mailer:    mailer.Default(),
+		List:      name,
+		Mailer:    m,
 	}, nil
 }
 
+// RouteExtension returns the address extension of the given route, that
+// comes after the recipient delimiter, prefixed with the list name if any.
+func (nl *Newsletter) RouteExtension(route string) string {
+	if nl.List == "" {
+		return route
+	}
+	return nl.List + "-" + route
+}
+
+// Domain returns the domain of the addresses of the newsletter.
+func (nl *Newsletter) Domain() string {
+	if nl.Config.Settings.Domain != "" {
+		return nl.Config.Settings.Domain
+	}
+	return nl.Hostname
+}
+
+// LocalPart returns the local part of the address of the newsletter.
+func (nl *Newsletter) LocalPart() string {
+	if nl.Config.Settings.LocalPart != "" {
+		return nl.Config.Settings.LocalPart
+	}
+	return nl.LocalUser
+}
+
+// Delimiter returns the recipient delimiter between the local part and the
+// extension of the route addresses.
+func (nl *Newsletter) Delimiter() string {
+	if nl.Config.Settings.Delimiter != "" {
+		return nl.Config.Settings.Delimiter
+	}
+	return "+"
+}
+
+func (nl *Newsletter) routeAddr(route string) string {
+	return nl.LocalPart() + nl.Delimiter() + nl.RouteExtension(route) + "@" + nl.Domain()
+}
+
 func (nl *Newsletter) PostmasterAddr() string {
-	return "postmaster@" + nl.Hostname
+	return "postmaster@" + nl.Domain()
 }
 
 func (nl *Newsletter) LocalUserAddr() string {
-	return nl.LocalUser + "@" + nl.Hostname
+	return nl.LocalPart() + "@" + nl.Domain()
 }
 
 func (nl *Newsletter) FromHdr() string {
@@ -101,15 +232,23 @@ func (nl *Newsletter) FromHdr() string {
 }
 
 func (nl *Newsletter) ListIdHdr() string {
+	id := nl.LocalPart() + "." + nl.Domain()
+	if nl.List != "" {
+		id = nl.List + "." + id
+	}
 	if nl.Config.Settings.DisplayName != "" {
-		return fmt.Sprintf(`%s <%s.%s>`, nl.Config.Settings.DisplayName, nl.LocalUser, nl.Hostname)
+		return fmt.Sprintf(`%s <%s>`, nl.Config.Settings.DisplayName, id)
 	} else {
-		return fmt.Sprintf("<%s.%s>", nl.LocalUser, nl.Hostname)
+		return fmt.Sprintf("<%s>", id)
 	}
 }
 
+func (nl *Newsletter) SubscribeAddr() string {
+	return nl.routeAddr(RouteSubscribe)
+}
+
 func (nl *Newsletter) UnsubscribeAddr() string {
-	return nl.LocalUser + "+" + RouteUnSubscribe + "@" + nl.Hostname
+	return nl.routeAddr(RouteUnSubscribe)
 }
 
 func (nl *Newsletter) ListUnsubscribeHdr() string {
@@ -117,11 +256,43 @@ func (nl *Newsletter) ListUnsubscribeHdr() string {
 }
 
 func (nl *Newsletter) SubscribeConfirmAddr() string {
-	return nl.LocalUser + "+" + RouteSubscribeConfirm + "@" + nl.Hostname
+	return nl.routeAddr(RouteSubscribeConfirm)
 }
 
 func (nl *Newsletter) SendConfirmAddr() string {
-	return nl.LocalUser + "+" + RouteSendConfirm + "@" + nl.Hostname
+	return nl.routeAddr(RouteSendConfirm)
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return base32.StdEncoding.EncodeToString(sum[0:32])
+}
+
+func (nl *Newsletter) HashWithSecret(s string) string {
+	return hashString(s + nl.Config.Secret)
+}
+
+func (nl *Newsletter) idPrefix() string {
+	if nl.List == "" {
+		return nl.LocalPart() + "-"
+	}
+	return nl.LocalPart() + "-" + nl.List + "-"
+}
+
+// GenerateId generates a Message-ID for this newsletter using the given hash.
+func (nl *Newsletter) GenerateId(hash string) string {
+	return nl.idPrefix() + hash + "@" + nl.Domain()
+}
+
+// GetHashFromId retrieves the hash from the given messageID of the form:
+// `USER-[LIST-]HASH@SERVER`
+func (nl *Newsletter) GetHashFromId(messageID string) (string, error) {
+	after, prefixFound := strings.CutPrefix(messageID, nl.idPrefix())
+	before, suffixFound := strings.CutSuffix(after, "@"+nl.Domain())
+	if !prefixFound || !suffixFound {
+		return "", errors.New("message ID doesn't match generated ID form")
+	}
+	return before, nil
 }
 
 // DefaultMail creates a new [mailer.Mail] struct with default values.
@@ -143,30 +314,40 @@ func (nl *Newsletter) DefaultMail(subject string, body string) *mailer.Mail {
 	}
 }
 
+// ConfirmSubscriptionMail creates the mail asking the given address to
+// confirm its subscription to the given topics by replying to it.
+func (nl *Newsletter) ConfirmSubscriptionMail(addr string, topics []string) *mailer.Mail {
+	body := nl.TitledBody(messages.ConfirmSubscription_body, messages.ConfirmSubscriptionAlt_body) + TopicsLine(topics)
+
+	mail := nl.DefaultMail(messages.ConfirmSubscription_subject.Print(), body)
+	mail.To = addr
+	mail.ReplyTo = nl.SubscribeConfirmAddr()
+	mail.Id = fmt.Sprintf("<%s>", nl.ConfirmID(addr, topics))
+	return mail
+}
+
 // SendPreviewMail sends a preview of the given mail to the owner of the
 // newsletter, appending (preview) to the original subject.
 func (nl *Newsletter) SendPreviewMail(mail mailer.Mail) error {
-	mail.To = nl.LocalUserAddr()
+	return nl.SendPreviewMailTo(mail, nl.LocalUserAddr())
+}
+
+// SendPreviewMailTo is like [Newsletter.SendPreviewMail] but sends the
+// preview to the given address, usually the one of an editor.
+func (nl *Newsletter) SendPreviewMailTo(mail mailer.Mail, addr string) error {
+	mail.To = addr
 	mail.Subject += " (preview)"
 
 	err := nl.Mailer.Send(&mail)
 	if err != nil {
 		return fmt.Errorf("send preview mail: %w", err)
 	}
-	fmt.Printf("📨 preview email sent to %s\n", nl.LocalUserAddr())
+	fmt.Printf("📨 preview email sent to %s\n", addr)
 	return nil
 }
 
 // SendNews sends the given mail to all the addresses subscribed to the
 // newsletter.
 func (nl *Newsletter) SendNews(mail *mailer.Mail) iter.Seq[error] {
-	return func(yield func(error) bool) {
-		for _, address := range nl.Config.Emails {
-			time.Sleep(200 * time.Millisecond)
-			mail.To = address
-			if !yield(nl.Mailer.Send(mail)) {
-				return
-			}
-		}
-	}
+	return nl.SendSegment(mail, "")
 }
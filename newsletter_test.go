@@ -23,7 +23,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/club-1/newsletter-go/v3"
 	"github.com/club-1/newsletter-go/v3/mailer"
@@ -43,14 +45,19 @@ func TestNew(t *testing.T) {
 	}
 	expectedConfig := &newsletter.Config{
 		Dir:    filepath.Join(homeDir, newsletter.ConfigPath),
-		Emails: []string{},
 		Secret: "BASIC_SECRET",
 		Settings: newsletter.Settings{
-			Title:       "Title",
-			DisplayName: "Display Name",
-			Language:    messages.LangFrench,
+			Version:           newsletter.SettingsVersion,
+			Title:             "Title",
+			DisplayName:       "Display Name",
+			Language:          messages.LangFrench,
+			LegacyTokensUntil: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
 		},
 	}
+	if count, _ := nl.Config.Subscribers.Count(); count != 0 {
+		t.Errorf("expected no subscribers, got: %d", count)
+	}
+	expectedConfig.Subscribers = nl.Config.Subscribers
 	if !reflect.DeepEqual(nl.Config, expectedConfig) {
 		t.Errorf("expected config:\n%#v\ngot:\n%#v", expectedConfig, nl.Config)
 	}
@@ -62,14 +69,21 @@ func TestNew(t *testing.T) {
 	}
 }
 
-func fakeNewsletter() *newsletter.Newsletter {
+func fakeNewsletter(t *testing.T) *newsletter.Newsletter {
+	t.Helper()
+	dir := t.TempDir()
+	subscribers, err := newsletter.OpenFileStore(filepath.Join(dir, newsletter.EmailsFile))
+	if err != nil {
+		t.Fatalf("open subscribers: %v", err)
+	}
+	if err := subscribers.Add(&newsletter.Subscriber{Address: "recipient@club1.fr"}); err != nil {
+		t.Fatalf("add subscriber: %v", err)
+	}
 	return &newsletter.Newsletter{
 		Config: &newsletter.Config{
-			Dir: "/home/user/.config/newsletter",
-			Emails: []string{
-				"recipient@club1.fr",
-			},
-			Secret: "BASIC_SECRET",
+			Dir:         dir,
+			Subscribers: subscribers,
+			Secret:      "BASIC_SECRET",
 			Settings: newsletter.Settings{
 				Title:       "Title",
 				DisplayName: "Display Name",
@@ -83,7 +97,7 @@ func fakeNewsletter() *newsletter.Newsletter {
 }
 
 func TestDefaultMail(t *testing.T) {
-	nl := fakeNewsletter()
+	nl := fakeNewsletter(t)
 	mail := nl.DefaultMail("Test subject", "Mail body")
 	expected := &mailer.Mail{
 		From:            "Display Name <user@club1.fr>",
@@ -102,7 +116,7 @@ Bye bye`,
 
 func TestSendPreviewMail(t *testing.T) {
 	var actual *mailer.Mail
-	nl := fakeNewsletter()
+	nl := fakeNewsletter(t)
 	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error {
 		actual = mail
 		return nil
@@ -130,7 +144,7 @@ func TestSendPreviewMail(t *testing.T) {
 
 func TestSendNews(t *testing.T) {
 	var actual *mailer.Mail
-	nl := fakeNewsletter()
+	nl := fakeNewsletter(t)
 	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error {
 		actual = mail
 		return nil
@@ -162,3 +176,135 @@ func TestSendNews(t *testing.T) {
 		t.Errorf("expected 1 sent email, got: %d", count)
 	}
 }
+
+func TestNewList(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	nl, err := newsletter.NewList("digest")
+	if err != nil {
+		t.Fatalf("new list: unexpected error: %v", err)
+	}
+	expectedDir := filepath.Join(homeDir, newsletter.ConfigPath, "digest")
+	if nl.Config.Dir != expectedDir {
+		t.Errorf("expected config dir %q, got %q", expectedDir, nl.Config.Dir)
+	}
+	if _, err := os.Stat(filepath.Join(expectedDir, newsletter.SecretFile)); err != nil {
+		t.Errorf("expected list secret to be generated: %v", err)
+	}
+
+	if _, err := newsletter.NewList("../escape"); err == nil {
+		t.Errorf("expected error for invalid list name")
+	}
+}
+
+func TestBaseConfigDir(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	cases := []struct {
+		name     string
+		env      string
+		xdg      string
+		expected string
+	}{
+		{"default", "", "", filepath.Join(homeDir, newsletter.ConfigPath)},
+		{"relative xdg", "", "config", filepath.Join(homeDir, newsletter.ConfigPath)},
+		{"xdg", "", "/srv/config", "/srv/config/newsletter"},
+		{"env", "/srv/news", "/srv/config", "/srv/news"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(newsletter.ConfigDirEnv, c.env)
+			t.Setenv("XDG_CONFIG_HOME", c.xdg)
+			dir, err := newsletter.BaseConfigDir()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dir != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, dir)
+			}
+		})
+	}
+}
+
+func TestNewListIn(t *testing.T) {
+	base := t.TempDir()
+	nl, err := newsletter.NewListIn(base, "digest")
+	if err != nil {
+		t.Fatalf("new list: unexpected error: %v", err)
+	}
+	expectedDir := filepath.Join(base, "digest")
+	if nl.Config.Dir != expectedDir {
+		t.Errorf("expected config dir %q, got %q", expectedDir, nl.Config.Dir)
+	}
+}
+
+func TestListAddrs(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.List = "digest"
+
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{"unsubscribe", nl.UnsubscribeAddr(), "user+digest-unsubscribe@club1.fr"},
+		{"subscribe-confirm", nl.SubscribeConfirmAddr(), "user+digest-subscribe-confirm@club1.fr"},
+		{"send-confirm", nl.SendConfirmAddr(), "user+digest-send-confirm@club1.fr"},
+		{"list-id", nl.ListIdHdr(), "Display Name <digest.user.club1.fr>"},
+		{"id", nl.GenerateId("HASH"), "user-digest-HASH@club1.fr"},
+	}
+	for _, c := range cases {
+		if c.actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, c.actual)
+		}
+	}
+
+	hash, err := nl.GetHashFromId("user-digest-HASH@club1.fr")
+	if err != nil || hash != "HASH" {
+		t.Errorf("expected hash %q, got %q (err: %v)", "HASH", hash, err)
+	}
+	if _, err := nl.GetHashFromId("user-HASH@club1.fr"); err == nil {
+		t.Errorf("expected error for the ID of another list")
+	}
+}
+
+func TestCustomAddrs(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.Domain = "news.example.org"
+	nl.Config.Settings.LocalPart = "info"
+	nl.Config.Settings.Delimiter = "-"
+	nl.Config.Settings.Title = ""
+	confirm := nl.ConfirmSubscriptionMail("recipient@club1.fr", nil)
+
+	cases := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{"local user", nl.LocalUserAddr(), "info@news.example.org"},
+		{"postmaster", nl.PostmasterAddr(), "postmaster@news.example.org"},
+		{"from", nl.FromHdr(), "Display Name <info@news.example.org>"},
+		{"subscribe", nl.SubscribeAddr(), "info-subscribe@news.example.org"},
+		{"list-unsubscribe", nl.ListUnsubscribeHdr(), "<mailto:info-unsubscribe@news.example.org>"},
+		{"list-id", nl.ListIdHdr(), "Display Name <info.news.example.org>"},
+		{"id", nl.GenerateId("HASH"), "info-HASH@news.example.org"},
+		{"confirm reply-to", confirm.ReplyTo, "info-subscribe-confirm@news.example.org"},
+	}
+	for _, c := range cases {
+		if c.actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, c.actual)
+		}
+	}
+
+	if !strings.Contains(confirm.Body, "info") || strings.Contains(confirm.Body, "user") {
+		t.Errorf("expected confirm body to name the list by its local part, got: %q", confirm.Body)
+	}
+
+	hash, err := nl.GetHashFromId("info-HASH@news.example.org")
+	if err != nil || hash != "HASH" {
+		t.Errorf("expected hash %q, got %q (err: %v)", "HASH", hash, err)
+	}
+	if _, err := nl.GetHashFromId("user-HASH@club1.fr"); err == nil {
+		t.Errorf("expected error for an ID of the default addresses")
+	}
+}
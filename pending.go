@@ -0,0 +1,245 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// Subscription policies of [Settings.SubscriptionPolicy].
+const (
+	// SubscriptionOpen lets anyone subscribe by confirming their address.
+	SubscriptionOpen = "open"
+	// SubscriptionApprove requires the confirmed subscriptions to be
+	// approved by the owner.
+	SubscriptionApprove = "approve"
+	// SubscriptionInvite only accepts the confirmations of the addresses
+	// invited by the owner, with `import -c`.
+	SubscriptionInvite = "invite"
+)
+
+// PurposeApprove is the purpose of the tokens of the approval requests.
+const PurposeApprove = "approve"
+
+// Events of the journal about pending subscriptions.
+const (
+	EventApprove = "approve"
+	EventReject  = "reject"
+)
+
+// ErrNotPending is returned when there is no pending subscription for an
+// address.
+var ErrNotPending = errors.New("no pending subscription")
+
+// PendingSubscription is a confirmed subscription that awaits the approval
+// of the owner.
+type PendingSubscription struct {
+	Address     string
+	Topics      []string `json:",omitempty"`
+	RequestedAt time.Time
+}
+
+// PendingSubscriptions returns the subscriptions awaiting approval, in the
+// order they were requested.
+func (c *Config) PendingSubscriptions() ([]PendingSubscription, error) {
+	lines, err := readLines(filepath.Join(c.Dir, PendingFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read pending subscriptions: %w", err)
+	}
+	var pending []PendingSubscription
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		var p PendingSubscription
+		if err := json.Unmarshal([]byte(line), &p); err != nil {
+			return nil, fmt.Errorf("parse pending subscription at line %d: %w", i+1, err)
+		}
+		pending = append(pending, p)
+	}
+	return pending, nil
+}
+
+func (c *Config) savePendingSubscriptions(pending []PendingSubscription) error {
+	path := filepath.Join(c.Dir, PendingFile)
+	if len(pending) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	lines := make([]string, len(pending))
+	for i := range pending {
+		line, err := json.Marshal(&pending[i])
+		if err != nil {
+			return fmt.Errorf("encode pending subscription: %w", err)
+		}
+		lines[i] = string(line)
+	}
+	return writeLines(lines, path)
+}
+
+// AddPendingSubscription adds the given subscription to the ones awaiting
+// approval, replacing the previous one of the same address.
+func (c *Config) AddPendingSubscription(p PendingSubscription) error {
+	pending, err := c.PendingSubscriptions()
+	if err != nil {
+		return err
+	}
+	pending = deletePending(pending, p.Address)
+	return c.savePendingSubscriptions(append(pending, p))
+}
+
+// TakePendingSubscription removes the pending subscription of the given
+// address and returns it, or [ErrNotPending] if there is none.
+func (c *Config) TakePendingSubscription(addr string) (*PendingSubscription, error) {
+	pending, err := c.PendingSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range pending {
+		if strings.EqualFold(pending[i].Address, addr) {
+			p := pending[i]
+			if err := c.savePendingSubscriptions(deletePending(pending, addr)); err != nil {
+				return nil, err
+			}
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNotPending, addr)
+}
+
+func deletePending(pending []PendingSubscription, addr string) []PendingSubscription {
+	var kept []PendingSubscription
+	for _, p := range pending {
+		if !strings.EqualFold(p.Address, addr) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// ApproveSubscription subscribes the address of the given pending
+// subscription, removing it from the pending ones.
+func (nl *Newsletter) ApproveSubscription(addr string) (*PendingSubscription, error) {
+	p, err := nl.Config.TakePendingSubscription(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := nl.Config.CheckAllowed(p.Address, true); err != nil {
+		return p, err
+	}
+	if err := nl.Config.SubscribeTopics(p.Address, p.Topics); err != nil {
+		return p, fmt.Errorf("subscribe address: %w", err)
+	}
+	if err := nl.Config.Unsuppress(p.Address); err != nil {
+		return p, fmt.Errorf("remove address from suppression list: %w", err)
+	}
+	return p, nil
+}
+
+// RejectSubscription removes the pending subscription of the given address
+// without subscribing it.
+func (nl *Newsletter) RejectSubscription(addr string) (*PendingSubscription, error) {
+	return nl.Config.TakePendingSubscription(addr)
+}
+
+// ApprovalID returns the Message-ID of the mail asking the owner to approve
+// the subscription of the given address.
+func (nl *Newsletter) ApprovalID(addr string) string {
+	encoded := tokenEncoding.EncodeToString([]byte(strings.ToLower(addr)))
+	return nl.GenerateId(nl.NewToken(PurposeApprove, nl.LocalUserAddr(), encoded))
+}
+
+// VerifyApprovalID checks that messageID has been generated by
+// [Newsletter.ApprovalID], and returns the address whose subscription is
+// to be approved.
+func (nl *Newsletter) VerifyApprovalID(messageID string) (string, error) {
+	token, err := nl.GetHashFromId(messageID)
+	if err != nil {
+		return "", err
+	}
+	data, err := nl.VerifyToken(token, PurposeApprove, nl.LocalUserAddr())
+	if err != nil {
+		return "", err
+	}
+	if len(data) != 1 {
+		return "", ErrInvalidToken
+	}
+	addr, err := tokenEncoding.DecodeString(data[0])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	return string(addr), nil
+}
+
+// ApprovalRequestMail creates the mail asking the owner to approve the
+// given pending subscription, by replying to it.
+func (nl *Newsletter) ApprovalRequestMail(p *PendingSubscription) *mailer.Mail {
+	body := fmt.Sprintf(messages.ApprovalRequest_body.Print(), p.Address) + TopicsLine(p.Topics)
+	body += fmt.Sprintf(messages.ApprovalRequestReply_line.Print(), nl.ActionKeyword(ActionApprove), nl.ActionKeyword(ActionReject))
+
+	mail := nl.DefaultMail(fmt.Sprintf(messages.ApprovalRequest_subject.Print(), p.Address), body)
+	mail.To = nl.LocalUserAddr()
+	mail.ReplyTo = nl.routeAddr(RouteModerate)
+	mail.Id = fmt.Sprintf("<%s>", nl.ApprovalID(p.Address))
+	return mail
+}
+
+// TitledBody formats msg with the title of the newsletter, or alt with the
+// local part of its address if it has no title, followed by the given
+// arguments.
+func (nl *Newsletter) TitledBody(msg messages.Message, alt messages.Message, args ...any) string {
+	if nl.Config.Settings.Title == "" {
+		return fmt.Sprintf(alt.Print(), append([]any{nl.LocalPart()}, args...)...)
+	}
+	return fmt.Sprintf(msg.Print(), append([]any{nl.Config.Settings.Title}, args...)...)
+}
+
+// ApprovedMail creates the mail notifying the address of the given pending
+// subscription that it has been approved.
+func (nl *Newsletter) ApprovedMail(p *PendingSubscription) *mailer.Mail {
+	body := nl.TitledBody(messages.SuccessfullSubscription_body, messages.SuccessfullSubscriptionAlt_body)
+	mail := nl.DefaultMail(messages.SuccessfullSubscription_subject.Print(), body+TopicsLine(p.Topics))
+	mail.To = p.Address
+	return mail
+}
+
+// RejectedMail creates the mail notifying the address of the given pending
+// subscription that it has been rejected.
+func (nl *Newsletter) RejectedMail(p *PendingSubscription) *mailer.Mail {
+	body := nl.TitledBody(messages.SubscriptionRejected_body, messages.SubscriptionRejectedAlt_body)
+	mail := nl.DefaultMail(messages.SubscriptionRejected_subject.Print(), body)
+	mail.To = p.Address
+	return mail
+}
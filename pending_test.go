@@ -0,0 +1,101 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestPendingSubscriptions(t *testing.T) {
+	nl := fakeNewsletter(t)
+	config := nl.Config
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, p := range []newsletter.PendingSubscription{
+		{Address: "first@club1.fr", RequestedAt: at},
+		{Address: "second@club1.fr", Topics: []string{"events"}, RequestedAt: at},
+		{Address: "First@club1.fr", Topics: []string{"digest"}, RequestedAt: at.Add(time.Hour)},
+	} {
+		if err := config.AddPendingSubscription(p); err != nil {
+			t.Fatalf("add pending subscription: %v", err)
+		}
+	}
+	expected := []newsletter.PendingSubscription{
+		{Address: "second@club1.fr", Topics: []string{"events"}, RequestedAt: at},
+		{Address: "First@club1.fr", Topics: []string{"digest"}, RequestedAt: at.Add(time.Hour)},
+	}
+	pending, err := config.PendingSubscriptions()
+	if err != nil {
+		t.Fatalf("list pending subscriptions: %v", err)
+	}
+	if !reflect.DeepEqual(pending, expected) {
+		t.Errorf("expected pending subscriptions:\n%#v\ngot:\n%#v", expected, pending)
+	}
+
+	p, err := nl.ApproveSubscription("first@club1.fr")
+	if err != nil {
+		t.Fatalf("approve subscription: %v", err)
+	}
+	if !reflect.DeepEqual(*p, expected[1]) {
+		t.Errorf("expected approved subscription %#v, got %#v", expected[1], *p)
+	}
+	sub, err := config.Subscribers.Get("First@club1.fr")
+	if err != nil {
+		t.Fatalf("get approved subscriber: %v", err)
+	}
+	if !reflect.DeepEqual(sub.Topics, []string{"digest"}) {
+		t.Errorf("expected approved subscriber topics, got: %v", sub.Topics)
+	}
+
+	if _, err := nl.RejectSubscription("second@club1.fr"); err != nil {
+		t.Fatalf("reject subscription: %v", err)
+	}
+	if _, err := config.Subscribers.Get("second@club1.fr"); !errors.Is(err, newsletter.ErrNotSubscribed) {
+		t.Errorf("expected rejected address not to be subscribed, got: %v", err)
+	}
+	if _, err := nl.ApproveSubscription("second@club1.fr"); !errors.Is(err, newsletter.ErrNotPending) {
+		t.Errorf("expected error %v, got: %v", newsletter.ErrNotPending, err)
+	}
+	if pending, err := config.PendingSubscriptions(); err != nil || len(pending) != 0 {
+		t.Errorf("expected no pending subscriptions, got: %v, %v", pending, err)
+	}
+}
+
+func TestApprovalID(t *testing.T) {
+	nl := fakeNewsletter(t)
+	id := nl.ApprovalID("Test@Club1.fr")
+	addr, err := nl.VerifyApprovalID(id)
+	if err != nil {
+		t.Fatalf("verify approval ID: %v", err)
+	}
+	if addr != "test@club1.fr" {
+		t.Errorf("expected address %q, got %q", "test@club1.fr", addr)
+	}
+
+	nl.Config.Settings.LocalPart = "other"
+	if _, err := nl.VerifyApprovalID(nl.SendID("other@club1.fr", "HASH")); !errors.Is(err, newsletter.ErrInvalidToken) {
+		t.Errorf("expected error %v for a send ID, got: %v", newsletter.ErrInvalidToken, err)
+	}
+}
@@ -0,0 +1,409 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// EventDistribute is the event of the journal about the sending of the
+// queued issues.
+const EventDistribute = "distribute"
+
+// Delays before an issue whose sending failed is tried again, doubled at
+// each failed attempt up to the maximum.
+const (
+	DistributionRetryDelay    = 5 * time.Minute
+	MaxDistributionRetryDelay = 24 * time.Hour
+)
+
+// Files of the queue, in [IssuesDir] or in the directory of an issue.
+const (
+	queueLockFile     = ".lock"
+	issueMailFile     = "mail.json"
+	issueProgressFile = "progress.jsonl"
+)
+
+// Delivery is the result of the sending of a queued issue to one
+// subscriber.
+type Delivery struct {
+	Address string
+	Error   string `json:",omitempty"`
+}
+
+// Distribution is the result of the sending of a queued issue to all the
+// subscribers.
+type Distribution struct {
+	Issue *Issue
+	// Deliveries are all the deliveries of the issue, including the ones
+	// of the previous attempts if it was interrupted.
+	Deliveries []Delivery
+}
+
+// Failures returns the deliveries that failed.
+func (d *Distribution) Failures() []Delivery {
+	var failures []Delivery
+	for _, delivery := range d.Deliveries {
+		if delivery.Error != "" {
+			failures = append(failures, delivery)
+		}
+	}
+	return failures
+}
+
+// Duration returns the time it took to send the issue, since the first
+// attempt.
+func (d *Distribution) Duration() time.Duration {
+	return d.Issue.SentAt.Sub(d.Issue.StartedAt)
+}
+
+// DistributionReport creates the mail reporting the given distribution to
+// the owner, or that it has been interrupted by the given error until the
+// retry time of the issue, in reply to the thread of the preview of the
+// issue.
+func (nl *Newsletter) DistributionReport(d *Distribution, err error) *mailer.Mail {
+	var mail *mailer.Mail
+	if err != nil {
+		body := fmt.Sprintf(messages.IssueReportError_body.Print(), d.Issue.Subject, d.Issue.ID, len(d.Deliveries), err, nl.FormatSendAt(d.Issue.RetryAt))
+		mail = nl.DefaultMail(fmt.Sprintf(messages.IssueReportError_subject.Print(), d.Issue.Subject), body)
+	} else {
+		body := fmt.Sprintf(messages.IssueReport_body.Print(), d.Issue.Subject, d.Issue.ID, len(d.Deliveries), d.Duration())
+		if failures := d.Failures(); len(failures) > 0 {
+			body += fmt.Sprintf(messages.IssueReportFailures_line.Print(), len(failures))
+			for _, f := range failures {
+				body += fmt.Sprintf("\n%s: %s", f.Address, f.Error)
+			}
+		}
+		mail = nl.DefaultMail(fmt.Sprintf(messages.IssueReport_subject.Print(), d.Issue.Subject), body)
+	}
+	mail.To = nl.LocalUserAddr()
+	if len(d.Issue.Thread) > 0 {
+		mail.InReplyTo = fmt.Sprintf("<%s>", d.Issue.Thread[len(d.Issue.Thread)-1])
+		refs := make([]string, len(d.Issue.Thread))
+		for i, id := range d.Issue.Thread {
+			refs[i] = fmt.Sprintf("<%s>", id)
+		}
+		mail.References = strings.Join(refs, " ")
+	}
+	return mail
+}
+
+// QueueIssue marks the given issue as confirmed and queues the given mail
+// to be sent to the subscribers by [Newsletter.RunQueue]. It returns
+// [ErrIssueSent] if the issue has already been queued, possibly by another
+// process, updating the issue in that case.
+func (nl *Newsletter) QueueIssue(issue *Issue, mail *mailer.Mail) error {
+	dir, err := nl.issueDir(issue.ID)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(mail)
+	if err != nil {
+		return fmt.Errorf("encode issue mail: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, issueQueuedFile), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if errors.Is(err, os.ErrExist) {
+		if current, err := nl.readIssue(issue.ID); err == nil {
+			*issue = *current
+		}
+		return fmt.Errorf("%w: %s", ErrIssueSent, issue.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("mark issue as queued: %w", err)
+	}
+	f.Close()
+	if err := writeFileAtomic(filepath.Join(dir, issueMailFile), content, 0600); err != nil {
+		return fmt.Errorf("write issue mail: %w", err)
+	}
+	issue.State = IssueQueued
+	issue.QueuedAt = nl.now().UTC().Truncate(time.Second)
+	return nl.saveIssue(dir, issue)
+}
+
+// QueuedIssues returns the issues that are queued or being sent, in the
+// order they were queued.
+func (nl *Newsletter) QueuedIssues() ([]Issue, error) {
+	issues, err := nl.Issues()
+	if err != nil {
+		return nil, err
+	}
+	issues = slices.DeleteFunc(issues, func(i Issue) bool {
+		return i.State != IssueQueued && i.State != IssueSending
+	})
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return a.QueuedAt.Compare(b.QueuedAt)
+	})
+	return issues, nil
+}
+
+// lockQueue takes the lock of the queue, that is released when the
+// process exits. It reports false if another process holds it.
+func (nl *Newsletter) lockQueue() (*os.File, bool, error) {
+	dir := filepath.Join(nl.Config.Dir, IssuesDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, false, fmt.Errorf("create issues directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, queueLockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, false, fmt.Errorf("open queue lock: %w", err)
+	}
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		f.Close()
+		return nil, false, nil
+	}
+	if err != nil {
+		f.Close()
+		return nil, false, fmt.Errorf("lock queue: %w", err)
+	}
+	return f, true, nil
+}
+
+// RunQueue sends the queued issues, until there are none left, including
+// the ones queued in the meantime. The issues scheduled later are left in
+// the queue. The sending of an interrupted issue is
+// resumed, skipping the subscribers it has already been sent to. Only one
+// process runs the queue at a time: it yields nothing if another one does.
+// It yields the distribution of each issue, with an error if it could not
+// be completed, and a nil distribution for the errors of the queue itself.
+// Each distribution is recorded in the journal. The issues that cannot be
+// sent are left in the queue, to be tried again by a later run after a
+// delay that grows with each failed attempt, see [DistributionRetryDelay].
+// The owner is only sent a report of a failure the first time it occurs.
+func (nl *Newsletter) RunQueue() iter.Seq2[*Distribution, error] {
+	return func(yield func(*Distribution, error) bool) {
+		failed := make(map[string]bool)
+		for {
+			lock, ok, err := nl.lockQueue()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !ok {
+				return
+			}
+			cont := nl.runQueue(failed, yield)
+			lock.Close()
+			if !cont {
+				return
+			}
+			// an issue may have been queued by a process that could not
+			// take the lock, after the queue was found empty
+			issue, err := nl.nextQueuedIssue(failed)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if issue == nil {
+				return
+			}
+		}
+	}
+}
+
+// nextQueuedIssue returns the first queued issue that is not in failed and
+// is not scheduled or retried later, or nil if there is none.
+func (nl *Newsletter) nextQueuedIssue(failed map[string]bool) (*Issue, error) {
+	issues, err := nl.QueuedIssues()
+	if err != nil {
+		return nil, err
+	}
+	now := nl.now()
+	for i := range issues {
+		if !failed[issues[i].ID] && !issues[i].Scheduled(now) && !issues[i].RetryAt.After(now) {
+			return &issues[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (nl *Newsletter) runQueue(failed map[string]bool, yield func(*Distribution, error) bool) bool {
+	for {
+		issue, err := nl.nextQueuedIssue(failed)
+		if err != nil {
+			return yield(nil, err)
+		}
+		if issue == nil {
+			return true
+		}
+		d, err := nl.distribute(issue)
+		entry := JournalEntry{Event: EventDistribute, Address: issue.Submitter, Outcome: OutcomeOK}
+		report := true
+		if err != nil {
+			failed[issue.ID] = true
+			err = fmt.Errorf("send issue %s: %w", issue.ID, err)
+			entry.Outcome, entry.Detail = OutcomeError, err.Error()
+			report = issue.LastError != err.Error()
+			if retryErr := nl.retryLater(issue, err); retryErr != nil {
+				err = errors.Join(err, retryErr)
+			}
+		} else {
+			failures := len(d.Failures())
+			entry.Detail = fmt.Sprintf("sent to %v subscribers with %v error(s), issue: %s", len(d.Deliveries), failures, d.Issue.ID)
+			if failures > 0 {
+				entry.Outcome = OutcomeError
+			}
+		}
+		if recordErr := nl.Record(entry); recordErr != nil {
+			err = errors.Join(err, recordErr)
+		}
+		if report {
+			if reportErr := nl.Mailer.Send(nl.DistributionReport(d, err)); reportErr != nil {
+				err = errors.Join(err, fmt.Errorf("send report: %w", reportErr))
+			}
+		}
+		if !yield(d, err) {
+			return false
+		}
+	}
+}
+
+// retryLater records the failed attempt to send the given issue, and
+// delays the next one.
+func (nl *Newsletter) retryLater(issue *Issue, err error) error {
+	dir, dirErr := nl.issueDir(issue.ID)
+	if dirErr != nil {
+		return dirErr
+	}
+	delay := DistributionRetryDelay
+	for range issue.Attempts {
+		delay = min(2*delay, MaxDistributionRetryDelay)
+	}
+	issue.Attempts++
+	issue.RetryAt = nl.now().UTC().Truncate(time.Second).Add(delay)
+	issue.LastError = err.Error()
+	if err := nl.saveIssue(dir, issue); err != nil {
+		return fmt.Errorf("record failed attempt: %w", err)
+	}
+	return nil
+}
+
+// distribute sends the given queued issue to the subscribers it has not
+// been sent to yet, and marks it as sent. The returned distribution is
+// never nil.
+func (nl *Newsletter) distribute(issue *Issue) (*Distribution, error) {
+	d := &Distribution{Issue: issue}
+	dir, err := nl.issueDir(issue.ID)
+	if err != nil {
+		return d, err
+	}
+	content, err := os.ReadFile(filepath.Join(dir, issueMailFile))
+	if err != nil {
+		return d, fmt.Errorf("read issue mail: %w", err)
+	}
+	var mail mailer.Mail
+	if err := json.Unmarshal(content, &mail); err != nil {
+		return d, fmt.Errorf("parse issue mail: %w", err)
+	}
+	d.Deliveries, err = readDeliveries(filepath.Join(dir, issueProgressFile))
+	if err != nil {
+		return d, err
+	}
+	done := make(map[string]bool, len(d.Deliveries))
+	for _, delivery := range d.Deliveries {
+		done[strings.ToLower(delivery.Address)] = true
+	}
+
+	if issue.State != IssueSending {
+		issue.State = IssueSending
+		issue.StartedAt = nl.now().UTC().Truncate(time.Second)
+		if err := nl.saveIssue(dir, issue); err != nil {
+			return d, err
+		}
+	}
+	progress, err := os.OpenFile(filepath.Join(dir, issueProgressFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return d, fmt.Errorf("open issue progress: %w", err)
+	}
+	defer progress.Close()
+	if info, err := progress.Stat(); err == nil && info.Size() > 0 {
+		// terminate the last line, that may have been truncated
+		progress.Write([]byte("\n"))
+	}
+	for sub, err := range nl.Segment(issue.Segment) {
+		if err != nil {
+			return d, fmt.Errorf("list subscribers: %w", err)
+		}
+		if done[strings.ToLower(sub.Address)] {
+			continue
+		}
+		time.Sleep(nl.SendInterval())
+		mail.To = sub.Address
+		delivery := Delivery{Address: sub.Address}
+		if err := nl.Mailer.Send(&mail); err != nil {
+			delivery.Error = err.Error()
+		}
+		d.Deliveries = append(d.Deliveries, delivery)
+		line, err := json.Marshal(&delivery)
+		if err != nil {
+			return d, fmt.Errorf("encode delivery: %w", err)
+		}
+		if _, err := progress.Write(append(line, '\n')); err != nil {
+			return d, fmt.Errorf("write issue progress: %w", err)
+		}
+	}
+
+	issue.State = IssueSent
+	issue.SentAt = nl.now().UTC().Truncate(time.Second)
+	if err := nl.saveIssue(dir, issue); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// readDeliveries reads the deliveries recorded in the given progress file.
+func readDeliveries(path string) ([]Delivery, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open issue progress: %w", err)
+	}
+	defer f.Close()
+	var deliveries []Delivery
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var d Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			// a line may be truncated or empty if the sending was
+			// interrupted while writing it
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read issue progress: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,220 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/mailer/mailertest"
+)
+
+func TestRunQueue(t *testing.T) {
+	nl := fakeNewsletter(t)
+	for _, addr := range []string{"second@club1.fr", "third@club1.fr"} {
+		if err := nl.Config.Subscribers.Add(&newsletter.Subscriber{Address: addr}); err != nil {
+			t.Fatalf("add subscriber: %v", err)
+		}
+	}
+	var sent []string
+	var reports []*mailer.Mail
+	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error {
+		switch mail.To {
+		case "third@club1.fr":
+			return errors.New("mailbox full")
+		case nl.LocalUserAddr():
+			reports = append(reports, mail)
+			return nil
+		}
+		sent = append(sent, mail.To)
+		return nil
+	}}
+
+	issue := &newsletter.Issue{ID: "ISSUE===", Subject: "Issue", Submitter: "user@club1.fr"}
+	if err := nl.CreateIssue(issue, "Body", nil); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if err := nl.QueueIssue(issue, &mailer.Mail{Subject: "[Title] Issue"}); err != nil {
+		t.Fatalf("queue issue: %v", err)
+	}
+	// the sending was interrupted after the first subscriber
+	progress := filepath.Join(nl.Config.Dir, newsletter.IssuesDir, "ISSUE===", "progress.jsonl")
+	if err := os.WriteFile(progress, []byte(`{"Address":"recipient@club1.fr"}`+"\n"+`{"Addr`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var distributions []*newsletter.Distribution
+	for d, err := range nl.RunQueue() {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		distributions = append(distributions, d)
+	}
+	if len(distributions) != 1 {
+		t.Fatalf("expected 1 distribution, got: %d", len(distributions))
+	}
+	if !reflect.DeepEqual(sent, []string{"second@club1.fr"}) {
+		t.Errorf("expected the issue to be sent to the remaining subscribers, got: %v", sent)
+	}
+	expected := []newsletter.Delivery{
+		{Address: "recipient@club1.fr"},
+		{Address: "second@club1.fr"},
+		{Address: "third@club1.fr", Error: "mailbox full"},
+	}
+	if !reflect.DeepEqual(distributions[0].Deliveries, expected) {
+		t.Errorf("expected deliveries:\n%#v\ngot:\n%#v", expected, distributions[0].Deliveries)
+	}
+	if failures := distributions[0].Failures(); len(failures) != 1 || failures[0] != expected[2] {
+		t.Errorf("expected 1 failure, got: %v", failures)
+	}
+	if distributions[0].Issue.State != newsletter.IssueSent {
+		t.Errorf("expected issue state %q, got %q", newsletter.IssueSent, distributions[0].Issue.State)
+	}
+
+	if len(reports) != 1 || !strings.Contains(reports[0].Body, "1 of the deliveries failed:\nthird@club1.fr: mailbox full") {
+		t.Errorf("expected a report listing the failure, got: %#v", reports)
+	}
+
+	journal, err := nl.Journal(newsletter.JournalFilter{})
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	if len(journal) != 1 || journal[0].Event != newsletter.EventDistribute || journal[0].Outcome != newsletter.OutcomeError {
+		t.Errorf("expected a failed distribution in the journal, got: %#v", journal)
+	}
+
+	for range nl.RunQueue() {
+		t.Errorf("expected the queue to be empty")
+	}
+}
+
+func TestRunQueueFailure(t *testing.T) {
+	nl := fakeNewsletter(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	nl.Now = func() time.Time { return now }
+	var reports []*mailer.Mail
+	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error {
+		reports = append(reports, mail)
+		return nil
+	}}
+
+	issue := &newsletter.Issue{ID: "ISSUE===", Subject: "Issue", Submitter: "user@club1.fr"}
+	if err := nl.CreateIssue(issue, "Body", nil); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if err := nl.QueueIssue(issue, &mailer.Mail{Subject: "[Title] Issue"}); err != nil {
+		t.Fatalf("queue issue: %v", err)
+	}
+	// the mail of the issue is lost, so that it can never be sent
+	if err := os.Remove(filepath.Join(nl.Config.Dir, newsletter.IssuesDir, "ISSUE===", "mail.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		after           time.Duration
+		expectedAttempt bool
+		expectedRetryAt time.Duration
+	}{
+		{0, true, 5 * time.Minute},
+		{time.Minute, false, 5 * time.Minute},
+		{5 * time.Minute, true, 15 * time.Minute},
+		{15 * time.Minute, true, 35 * time.Minute},
+	}
+	for i, c := range cases {
+		now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC).Add(c.after)
+		attempts := 0
+		for d, err := range nl.RunQueue() {
+			attempts++
+			if err == nil || d == nil {
+				t.Errorf("run %d: expected a failed distribution, got: %v, %v", i, d, err)
+			}
+		}
+		if attempts != 0 != c.expectedAttempt {
+			t.Errorf("run %d: expected attempt %v, got %v attempts", i, c.expectedAttempt, attempts)
+		}
+		issue, err := nl.Issue("ISSUE===")
+		if err != nil {
+			t.Fatalf("run %d: get issue: %v", i, err)
+		}
+		expected := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC).Add(c.expectedRetryAt)
+		if issue.State != newsletter.IssueQueued || !issue.RetryAt.Equal(expected) {
+			t.Errorf("run %d: expected issue queued until %v, got %q until %v", i, expected, issue.State, issue.RetryAt)
+		}
+	}
+	if len(reports) != 1 || !strings.Contains(reports[0].Body, "read issue mail") {
+		t.Errorf("expected a single report of the failure, got: %#v", reports)
+	}
+}
+
+func TestDistributionReport(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.TimeZone = "UTC"
+	issue := &newsletter.Issue{
+		ID:        "ISSUE===",
+		Subject:   "Issue",
+		Thread:    []string{"preview@club1.fr", "confirm@club1.fr"},
+		StartedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		SentAt:    time.Date(2026, 10, 1, 12, 1, 30, 0, time.UTC),
+		RetryAt:   time.Date(2026, 10, 1, 12, 5, 0, 0, time.UTC),
+	}
+	d := &newsletter.Distribution{Issue: issue, Deliveries: []newsletter.Delivery{{Address: "recipient@club1.fr"}}}
+	cases := []struct {
+		name            string
+		err             error
+		expectedSubject string
+		expectedBody    string
+	}{
+		{
+			name:            "sent",
+			expectedSubject: "[Title] Newsletter sent: Issue",
+			expectedBody:    "The newsletter \"Issue\" (issue ISSUE===) has been sent to 1 subscribers in 1m30s.",
+		},
+		{
+			name:            "interrupted",
+			err:             errors.New("disk full"),
+			expectedSubject: "[Title] Newsletter sending interrupted: Issue",
+			expectedBody:    "The sending of the newsletter \"Issue\" (issue ISSUE===) has been interrupted after 1 deliveries, because of the error:\ndisk full\n\nIt will be resumed after 2026-10-01 12:05 UTC.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mail := nl.DistributionReport(d, c.err)
+			if mail.To != nl.LocalUserAddr() {
+				t.Errorf("expected report to the owner, got: %q", mail.To)
+			}
+			if mail.Subject != c.expectedSubject {
+				t.Errorf("expected subject %q, got %q", c.expectedSubject, mail.Subject)
+			}
+			if !strings.HasPrefix(mail.Body, c.expectedBody) {
+				t.Errorf("expected body to start with:\n%s\ngot:\n%s", c.expectedBody, mail.Body)
+			}
+			if mail.InReplyTo != "<confirm@club1.fr>" || mail.References != "<preview@club1.fr> <confirm@club1.fr>" {
+				t.Errorf("expected report in the thread of the issue, got: %q, %q", mail.InReplyTo, mail.References)
+			}
+		})
+	}
+}
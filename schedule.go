@@ -0,0 +1,141 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SendAtMarker starts the subject of a news sent by email that must only be
+// sent at a given time, as in "[Send-At: 2026-11-01 09:00] Subject".
+const SendAtMarker = "[Send-At:"
+
+// SendAtLayout is the layout of the sending times given in the local time
+// zone of the list.
+const SendAtLayout = "2006-01-02 15:04"
+
+// ErrIssueNotScheduled is returned when cancelling an issue that is not
+// waiting in the queue, as it is pending, being sent or sent.
+var ErrIssueNotScheduled = errors.New("issue not scheduled")
+
+// Location returns the time zone of [Settings.TimeZone], or the local time
+// zone of the server if it is not set or invalid.
+func (s *Settings) Location() *time.Location {
+	if s.TimeZone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// ParseSendAt parses the time at which an issue must be sent, given as
+// YYYY-MM-DD HH:MM, in the time zone of the list or followed by a time
+// zone name or a numeric offset, or in RFC 3339 format. The time must not
+// be in the past. The time is returned in UTC.
+func (nl *Newsletter) ParseSendAt(s string) (time.Time, error) {
+	t, err := parseSendAt(strings.TrimSpace(s), nl.Config.Settings.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid sending time %q, must be YYYY-MM-DD HH:MM, optionally followed by a time zone, or RFC 3339", s)
+	}
+	if t.Before(nl.now()) {
+		return time.Time{}, fmt.Errorf("sending time %s is in the past", nl.FormatSendAt(t))
+	}
+	return t.UTC(), nil
+}
+
+func parseSendAt(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	date, clock, _ := strings.Cut(s, " ")
+	clock, zone, hasZone := strings.Cut(strings.TrimSpace(clock), " ")
+	s = date + " " + clock
+	if !hasZone {
+		return time.ParseInLocation(SendAtLayout, s, loc)
+	}
+	zone = strings.TrimSpace(zone)
+	if t, err := time.Parse(SendAtLayout+" -0700", s+" "+zone); err == nil {
+		return t, nil
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(SendAtLayout, s, loc)
+}
+
+// FormatSendAt formats the given sending time in the time zone of the
+// list, with the abbreviation of the zone.
+func (nl *Newsletter) FormatSendAt(t time.Time) string {
+	return t.In(nl.Config.Settings.Location()).Format(SendAtLayout + " MST")
+}
+
+// CutSendAt removes the [SendAtMarker] from the start of the given subject,
+// and returns the subject without it, the sending time it contains, and
+// whether it was found.
+func CutSendAt(subject string) (string, string, bool) {
+	trimmed := strings.TrimSpace(subject)
+	if len(trimmed) < len(SendAtMarker) || !strings.EqualFold(trimmed[:len(SendAtMarker)], SendAtMarker) {
+		return subject, "", false
+	}
+	at, rest, ok := strings.Cut(trimmed[len(SendAtMarker):], "]")
+	if !ok {
+		return subject, "", false
+	}
+	return strings.TrimSpace(rest), strings.TrimSpace(at), true
+}
+
+// Scheduled reports whether the issue must only be sent later than the
+// given time.
+func (i *Issue) Scheduled(now time.Time) bool {
+	return i.SendAt.After(now)
+}
+
+// CancelScheduledIssue removes the queued issue with the given ID, that has
+// not started to be sent. It returns [ErrIssueNotScheduled] if the issue is
+// not waiting in the queue, and an error if the queue is being run.
+func (nl *Newsletter) CancelScheduledIssue(id string) (*Issue, error) {
+	lock, ok, err := nl.lockQueue()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("the queue is being run, try again later")
+	}
+	defer lock.Close()
+	issue, err := nl.readIssue(id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.State != IssueQueued {
+		state := issue.State
+		if state == IssuePending {
+			state = "pending"
+		}
+		return issue, fmt.Errorf("%w: %s is %s", ErrIssueNotScheduled, id, state)
+	}
+	return issue, nl.DiscardIssue(id)
+}
@@ -0,0 +1,148 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/mailer/mailertest"
+)
+
+func TestParseSendAt(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.TimeZone = "Europe/Paris"
+	nl.Now = func() time.Time { return time.Date(2026, 10, 31, 20, 0, 0, 0, time.UTC) }
+	cases := []struct {
+		name     string
+		value    string
+		expected time.Time
+		err      string
+	}{
+		{"settings zone", "2026-11-01 09:00", time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC), ""},
+		{"zone name", "2026-11-01 09:00 America/New_York", time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC), ""},
+		{"offset", " 2026-11-01 09:00 +0000 ", time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC), ""},
+		{"RFC 3339", "2026-11-01T09:00:00+02:00", time.Date(2026, 11, 1, 7, 0, 0, 0, time.UTC), ""},
+		{"past", "2026-10-31 20:00", time.Time{}, `sending time 2026-10-31 20:00 CET is in the past`},
+		{"unknown zone", "2026-11-01 09:00 Mars/Olympus", time.Time{}, `invalid sending time "2026-11-01 09:00 Mars/Olympus"`},
+		{"invalid", "tomorrow", time.Time{}, `invalid sending time "tomorrow"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := nl.ParseSendAt(c.value)
+			if c.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+					t.Errorf("expected error %q, got: %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(c.expected) || got.Location() != time.UTC {
+				t.Errorf("expected %v, got %v", c.expected, got)
+			}
+		})
+	}
+}
+
+func TestCutSendAt(t *testing.T) {
+	cases := []struct {
+		subject         string
+		expectedSubject string
+		expectedAt      string
+		expectedFound   bool
+	}{
+		{"News", "News", "", false},
+		{"[Send-At: 2026-11-01 09:00] News", "News", "2026-11-01 09:00", true},
+		{" [send-at:2026-11-01 09:00 Europe/Paris]News", "News", "2026-11-01 09:00 Europe/Paris", true},
+		{"[Send-At: 2026-11-01 09:00 News", "[Send-At: 2026-11-01 09:00 News", "", false},
+		{"Re: [Send-At: 2026-11-01 09:00] News", "Re: [Send-At: 2026-11-01 09:00] News", "", false},
+	}
+	for _, c := range cases {
+		subject, at, found := newsletter.CutSendAt(c.subject)
+		if subject != c.expectedSubject || at != c.expectedAt || found != c.expectedFound {
+			t.Errorf("%q: expected (%q, %q, %v), got (%q, %q, %v)", c.subject, c.expectedSubject, c.expectedAt, c.expectedFound, subject, at, found)
+		}
+	}
+}
+
+func TestScheduledIssue(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.Topics = []string{"events", "jobs"}
+	for _, topic := range nl.Config.Settings.Topics {
+		if err := nl.Config.Subscribers.Add(&newsletter.Subscriber{Address: topic + "@club1.fr", Topics: []string{topic}}); err != nil {
+			t.Fatalf("add subscriber: %v", err)
+		}
+	}
+	now := time.Date(2026, 10, 31, 20, 0, 0, 0, time.UTC)
+	nl.Now = func() time.Time { return now }
+	var sent []string
+	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error {
+		if mail.To != nl.LocalUserAddr() {
+			sent = append(sent, mail.To)
+		}
+		return nil
+	}}
+
+	sendAt := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+	for _, id := range []string{"SCHEDULED===", "CANCELLED==="} {
+		issue := &newsletter.Issue{ID: id, Subject: "Issue", Segment: "events", SendAt: sendAt}
+		if err := nl.CreateIssue(issue, "Body", nil); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		if err := nl.QueueIssue(issue, &mailer.Mail{Subject: "[Title] Issue"}); err != nil {
+			t.Fatalf("queue issue: %v", err)
+		}
+	}
+	for range nl.RunQueue() {
+		t.Errorf("expected the scheduled issues not to be sent yet")
+	}
+
+	if _, err := nl.CancelScheduledIssue("CANCELLED==="); err != nil {
+		t.Errorf("cancel: unexpected error: %v", err)
+	}
+	if _, err := nl.CancelScheduledIssue("CANCELLED==="); !errors.Is(err, newsletter.ErrNoIssue) {
+		t.Errorf("cancel again: expected %v, got: %v", newsletter.ErrNoIssue, err)
+	}
+
+	now = sendAt
+	var distributions []*newsletter.Distribution
+	for d, err := range nl.RunQueue() {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		distributions = append(distributions, d)
+	}
+	if len(distributions) != 1 || distributions[0].Issue.ID != "SCHEDULED===" {
+		t.Fatalf("expected the scheduled issue to be sent, got: %v", distributions)
+	}
+	if !reflect.DeepEqual(sent, []string{"recipient@club1.fr", "events@club1.fr"}) {
+		t.Errorf("expected the issue to be sent to its segment, got: %v", sent)
+	}
+	if _, err := nl.CancelScheduledIssue("SCHEDULED==="); !errors.Is(err, newsletter.ErrIssueNotScheduled) {
+		t.Errorf("cancel sent issue: expected %v, got: %v", newsletter.ErrIssueNotScheduled, err)
+	}
+}
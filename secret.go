@@ -0,0 +1,98 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OldSecret is a previous secret of the newsletter, that is still accepted
+// to verify tokens until it retires.
+type OldSecret struct {
+	Value    string
+	RetireAt time.Time
+}
+
+// parseSecrets parses the content of the secret file, which holds the
+// current secret on its first line, followed by the old secrets and their
+// retirement date, separated by a space.
+func parseSecrets(content string) (string, []OldSecret, error) {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	current := strings.TrimSpace(lines[0])
+	var old []OldSecret
+	for i, line := range lines[1:] {
+		value, date, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			return "", nil, fmt.Errorf("line %d: missing retirement date", i+2)
+		}
+		retireAt, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		old = append(old, OldSecret{Value: value, RetireAt: retireAt})
+	}
+	return current, old, nil
+}
+
+func formatSecrets(current string, old []OldSecret) string {
+	var b strings.Builder
+	b.WriteString(current + "\n")
+	for _, secret := range old {
+		fmt.Fprintf(&b, "%s %s\n", secret.Value, secret.RetireAt.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// Secrets returns the secrets that are accepted at the given time, starting
+// with the current one.
+func (c *Config) Secrets(now time.Time) []string {
+	secrets := []string{c.Secret}
+	for _, secret := range c.OldSecrets {
+		if now.Before(secret.RetireAt) {
+			secrets = append(secrets, secret.Value)
+		}
+	}
+	return secrets
+}
+
+// RotateSecret replaces the current secret by a newly generated one. The
+// previous secret is still accepted during the given grace period, and the
+// old secrets that have retired are forgotten.
+func (c *Config) RotateSecret(grace time.Duration) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	old := []OldSecret{{Value: c.Secret, RetireAt: now.Add(grace)}}
+	for _, secret := range c.OldSecrets {
+		if now.Before(secret.RetireAt) {
+			old = append(old, secret)
+		}
+	}
+	secret := randString()
+
+	secretFilePath := filepath.Join(c.Dir, SecretFile)
+	if err := writeFileAtomic(secretFilePath, []byte(formatSecrets(secret, old)), 0660); err != nil {
+		return fmt.Errorf("could not save secrets: %w", err)
+	}
+	c.Secret = secret
+	c.OldSecrets = old
+	return nil
+}
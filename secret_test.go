@@ -0,0 +1,111 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestRotateSecret(t *testing.T) {
+	dir := t.TempDir()
+	config, err := newsletter.InitConfig(dir)
+	if err != nil {
+		t.Fatalf("init config: %v", err)
+	}
+	defer config.Close()
+	nl := &newsletter.Newsletter{Config: config, Hostname: "club1.fr", LocalUser: "user"}
+	token := nl.NewToken(newsletter.PurposeSubscribe, "a@club1.fr")
+	previous := config.Secret
+
+	if err := config.RotateSecret(time.Hour); err != nil {
+		t.Fatalf("rotate secret: %v", err)
+	}
+	if config.Secret == previous {
+		t.Errorf("expected a new secret")
+	}
+	if _, err := nl.VerifyToken(token, newsletter.PurposeSubscribe, "a@club1.fr"); err != nil {
+		t.Errorf("expected token signed by the previous secret to be valid, got: %v", err)
+	}
+
+	reloaded, err := newsletter.InitConfig(dir)
+	if err != nil {
+		t.Fatalf("reload config: %v", err)
+	}
+	defer reloaded.Close()
+	if reloaded.Secret != config.Secret {
+		t.Errorf("expected secret %q, got %q", config.Secret, reloaded.Secret)
+	}
+	if !reflect.DeepEqual(reloaded.OldSecrets, config.OldSecrets) {
+		t.Errorf("expected old secrets:\n%#v\ngot:\n%#v", config.OldSecrets, reloaded.OldSecrets)
+	}
+
+	nl.Now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := nl.VerifyToken(token, newsletter.PurposeSubscribe, "a@club1.fr"); !errors.Is(err, newsletter.ErrInvalidToken) {
+		t.Errorf("expected token signed by a retired secret to be invalid, got: %v", err)
+	}
+}
+
+func TestInitConfigSecrets(t *testing.T) {
+	cases := []struct {
+		name        string
+		content     string
+		expectedOld []newsletter.OldSecret
+		expectedErr bool
+	}{
+		{"single", "CURRENT\n", nil, false},
+		{"old", "CURRENT\nOLD 2026-10-01T12:00:00Z\n", []newsletter.OldSecret{
+			{Value: "OLD", RetireAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)},
+		}, false},
+		{"missing date", "CURRENT\nOLD\n", nil, true},
+		{"invalid date", "CURRENT\nOLD tomorrow\n", nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, newsletter.SecretFile), []byte(c.content), 0660); err != nil {
+				t.Fatalf("write secret: %v", err)
+			}
+			config, err := newsletter.InitConfig(dir)
+			if c.expectedErr {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer config.Close()
+			if config.Secret != "CURRENT" {
+				t.Errorf("expected secret %q, got %q", "CURRENT", config.Secret)
+			}
+			if !reflect.DeepEqual(config.OldSecrets, c.expectedOld) {
+				t.Errorf("expected old secrets:\n%#v\ngot:\n%#v", c.expectedOld, config.OldSecrets)
+			}
+		})
+	}
+}
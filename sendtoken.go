@@ -0,0 +1,92 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readSendToken reads the secret token of the send address, which is empty
+// if it has not been generated.
+func readSendToken(configDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(configDir, SendTokenFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+// RotateSendToken generates a new secret token for the send address,
+// replacing the previous one.
+func (c *Config) RotateSendToken() error {
+	key := make([]byte, 15)
+	rand.Read(key)
+	token := strings.ToLower(tokenEncoding.EncodeToString(key))
+	if err := writeFileAtomic(filepath.Join(c.Dir, SendTokenFile), []byte(token+"\n"), 0600); err != nil {
+		return fmt.Errorf("could not save send token: %w", err)
+	}
+	c.SendToken = token
+	return nil
+}
+
+// RemoveSendToken removes the secret token of the send address.
+func (c *Config) RemoveSendToken() error {
+	err := os.Remove(filepath.Join(c.Dir, SendTokenFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove send token: %w", err)
+	}
+	c.SendToken = ""
+	return nil
+}
+
+// IsSendTokenRoute reports whether route is the route of the secret send
+// address.
+func (nl *Newsletter) IsSendTokenRoute(route string) bool {
+	token, ok := strings.CutPrefix(route, RouteSend+"-")
+	return ok && nl.Config.SendToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(nl.Config.SendToken)) == 1
+}
+
+// SendTokenRoute returns the route of the secret send address, or an empty
+// string if there is none.
+func (nl *Newsletter) SendTokenRoute() string {
+	if nl.Config.SendToken == "" {
+		return ""
+	}
+	return RouteSend + "-" + nl.Config.SendToken
+}
+
+// SendTokenAddr returns the secret send address, or an empty string if
+// there is none.
+func (nl *Newsletter) SendTokenAddr() string {
+	if nl.Config.SendToken == "" {
+		return ""
+	}
+	return nl.routeAddr(nl.SendTokenRoute())
+}
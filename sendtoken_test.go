@@ -0,0 +1,78 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestSendToken(t *testing.T) {
+	nl := fakeNewsletter(t)
+	if addr := nl.SendTokenAddr(); addr != "" {
+		t.Errorf("expected no send address, got %q", addr)
+	}
+	if nl.IsSendTokenRoute("send-") {
+		t.Errorf("expected empty token not to be accepted")
+	}
+
+	if err := nl.Config.RotateSendToken(); err != nil {
+		t.Fatalf("rotate send token: %v", err)
+	}
+	token := nl.Config.SendToken
+	if !regexp.MustCompile(`^[a-z2-7]{24}$`).MatchString(token) {
+		t.Errorf("unexpected token format: %q", token)
+	}
+	if addr, expected := nl.SendTokenAddr(), "user+send-"+token+"@club1.fr"; addr != expected {
+		t.Errorf("expected send address %q, got %q", expected, addr)
+	}
+	if !nl.IsSendTokenRoute("send-" + token) {
+		t.Errorf("expected token route to be accepted")
+	}
+	if nl.IsSendTokenRoute(newsletter.RouteSendConfirm) {
+		t.Errorf("expected send-confirm route not to be a token route")
+	}
+
+	config, err := newsletter.InitConfig(nl.Config.Dir)
+	if err != nil {
+		t.Fatalf("init config: %v", err)
+	}
+	if config.SendToken != token {
+		t.Errorf("expected loaded token %q, got %q", token, config.SendToken)
+	}
+	info, err := os.Stat(filepath.Join(nl.Config.Dir, newsletter.SendTokenFile))
+	if err != nil {
+		t.Fatalf("stat send token file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected send token file mode 0600, got %v", perm)
+	}
+
+	if err := nl.Config.RemoveSendToken(); err != nil {
+		t.Fatalf("remove send token: %v", err)
+	}
+	if nl.IsSendTokenRoute("send-" + token) {
+		t.Errorf("expected removed token route not to be accepted")
+	}
+}
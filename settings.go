@@ -0,0 +1,237 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// SettingsVersion is the version of the settings schema written by this
+// version of the program. It must be incremented with a migration each time
+// a field is added, as the previous versions reject the unknown fields.
+const SettingsVersion = 2
+
+// Authentication methods that can be required by [Settings.RequireAuth].
+const (
+	AuthDMARC = "dmarc"
+	AuthDKIM  = "dkim"
+	AuthSPF   = "spf"
+)
+
+var delimiterRegexp = regexp.MustCompile(`^[-+_.=]$`)
+
+// settingsMigrations migrate the settings from the version of their index
+// to the next one.
+var settingsMigrations = [SettingsVersion]func(s *Settings){
+	// Accept the confirmation IDs generated by previous versions until
+	// they would have expired.
+	func(s *Settings) {
+		if s.LegacyTokensUntil.IsZero() {
+			s.LegacyTokensUntil = time.Now().UTC().Truncate(time.Second).Add(s.ConfirmExpiryOrDefault())
+		}
+	},
+	// Domain, LocalPart, Delimiter, SubscriptionPolicy, Editors,
+	// SendApprovals, RequireSendToken, SendKeyword, CancelKeyword and
+	// TimeZone are added, their zero values keep the previous behaviour.
+	func(s *Settings) {},
+}
+
+// SettingsError is an error of a field of the settings.
+type SettingsError struct {
+	// Field is the path of the field, like "Topics[1]".
+	Field  string
+	Reason string
+}
+
+func (e *SettingsError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+func unsupportedVersion(version int) *SettingsError {
+	return &SettingsError{"Version", fmt.Sprintf("unsupported version %d, the newest known is %d", version, SettingsVersion)}
+}
+
+// Migrate migrates the settings to [SettingsVersion], and reports whether
+// they have been modified.
+func (s *Settings) Migrate() (bool, error) {
+	if s.Version > SettingsVersion {
+		return false, unsupportedVersion(s.Version)
+	}
+	migrated := s.Version < SettingsVersion
+	for ; s.Version < SettingsVersion; s.Version++ {
+		settingsMigrations[s.Version](s)
+	}
+	return migrated, nil
+}
+
+// Validate checks the settings, and returns the errors of all their
+// invalid fields as [*SettingsError] joined together.
+func (s *Settings) Validate() error {
+	var errs []error
+	invalid := func(field string, format string, v ...any) {
+		errs = append(errs, &SettingsError{field, fmt.Sprintf(format, v...)})
+	}
+
+	if s.Version < 0 || s.Version > SettingsVersion {
+		invalid("Version", "unsupported version %d, the newest known is %d", s.Version, SettingsVersion)
+	}
+	switch s.Language {
+	case "", messages.LangEnglish, messages.LangFrench:
+	default:
+		invalid("Language", "unknown language %q, must be %q or %q", s.Language, messages.LangEnglish, messages.LangFrench)
+	}
+	if strings.ContainsAny(s.Domain, "@ \t") {
+		invalid("Domain", "invalid domain %q", s.Domain)
+	}
+	if strings.ContainsAny(s.LocalPart, "@ \t") {
+		invalid("LocalPart", "invalid local part %q", s.LocalPart)
+	}
+	if s.Delimiter != "" && !delimiterRegexp.MatchString(s.Delimiter) {
+		invalid("Delimiter", "invalid delimiter %q, must be a single character among \"+-_.=\"", s.Delimiter)
+	}
+	switch s.Store {
+	case "", StoreFile, StoreBolt:
+	default:
+		invalid("Store", "unknown store %q, must be %q or %q", s.Store, StoreFile, StoreBolt)
+	}
+	for i, topic := range s.Topics {
+		field := fmt.Sprintf("Topics[%d]", i)
+		if err := ValidateTopic(topic); err != nil {
+			invalid(field, "%v", err)
+		} else if slices.Index(s.Topics, topic) < i {
+			invalid(field, "duplicate topic %q", topic)
+		}
+	}
+	if s.ConfirmExpiry < 0 {
+		invalid("ConfirmExpiry", "must not be negative")
+	}
+	for i, domain := range s.BlockedDomains {
+		if strings.TrimPrefix(domain, "@") == "" || strings.ContainsAny(domain, " \t") {
+			invalid(fmt.Sprintf("BlockedDomains[%d]", i), "invalid domain %q", domain)
+		}
+	}
+	for i, pattern := range s.BlockedPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			invalid(fmt.Sprintf("BlockedPatterns[%d]", i), "%v", err)
+		}
+	}
+	switch s.SubscriptionPolicy {
+	case "", SubscriptionOpen, SubscriptionApprove, SubscriptionInvite:
+	default:
+		invalid("SubscriptionPolicy", "unknown policy %q, must be %q, %q or %q", s.SubscriptionPolicy, SubscriptionOpen, SubscriptionApprove, SubscriptionInvite)
+	}
+	for i, editor := range s.Editors {
+		if local, domain, ok := strings.Cut(editor, "@"); !ok || local == "" || domain == "" || strings.ContainsAny(editor, " \t<>") {
+			invalid(fmt.Sprintf("Editors[%d]", i), "invalid address %q", editor)
+		}
+	}
+	if s.SendApprovals < 0 {
+		invalid("SendApprovals", "must not be negative")
+	} else if s.SendApprovals > len(s.Editors)+1 {
+		invalid("SendApprovals", "cannot be more than the %d editors, including the owner", len(s.Editors)+1)
+	}
+	if s.SendKeyword != strings.TrimSpace(s.SendKeyword) || strings.Contains(s.SendKeyword, "\n") {
+		invalid("SendKeyword", "invalid keyword %q", s.SendKeyword)
+	}
+	if s.CancelKeyword != strings.TrimSpace(s.CancelKeyword) || strings.Contains(s.CancelKeyword, "\n") {
+		invalid("CancelKeyword", "invalid keyword %q", s.CancelKeyword)
+	}
+	if strings.EqualFold(s.SendKeywordOrDefault(), s.CancelKeywordOrDefault()) {
+		invalid("CancelKeyword", "must be different from the send keyword")
+	}
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			invalid("TimeZone", "unknown time zone %q", s.TimeZone)
+		}
+	}
+	if len(s.RequireAuth) > 0 && s.AuthServID == "" {
+		invalid("RequireAuth", "requires AuthServID to be set")
+	}
+	for _, route := range slices.Sorted(maps.Keys(s.RequireAuth)) {
+		topic, isTopicRoute := strings.CutPrefix(route, RouteSubscribe+"-")
+		if !slices.Contains(Routes[:], route) && !(isTopicRoute && slices.Contains(s.Topics, topic)) {
+			invalid("RequireAuth", "unknown route %q", route)
+		}
+		for i, method := range s.RequireAuth[route] {
+			switch method {
+			case AuthDMARC, AuthDKIM, AuthSPF:
+			default:
+				invalid(fmt.Sprintf("RequireAuth[%q][%d]", route, i), "unknown method %q, must be %q, %q or %q", method, AuthDMARC, AuthDKIM, AuthSPF)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// decodeStrict decodes the JSON data into v, rejecting unknown fields.
+func decodeStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line := bytes.Count(data[:syntaxErr.Offset], []byte("\n")) + 1
+		return fmt.Errorf("line %d: %w", line, err)
+	}
+	return err
+}
+
+// decodeSettings decodes the JSON settings, rejecting unknown fields. The
+// settings of a newer version are reported as such rather than by their
+// unknown fields, so that the owner knows to upgrade the program.
+func decodeSettings(data []byte) (Settings, error) {
+	var settings Settings
+	err := decodeStrict(data, &settings)
+	if err != nil {
+		var version struct{ Version int }
+		if json.Unmarshal(data, &version) == nil && version.Version > SettingsVersion {
+			return settings, unsupportedVersion(version.Version)
+		}
+	}
+	return settings, err
+}
+
+// ValidateSettingsFile checks the settings file at the given path, as if it
+// was migrated to [SettingsVersion], without modifying it.
+func ValidateSettingsFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	settings, err := decodeSettings(data)
+	if err != nil {
+		return err
+	}
+	if _, err := settings.Migrate(); err != nil {
+		return err
+	}
+	return settings.Validate()
+}
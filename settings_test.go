@@ -0,0 +1,110 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestValidateSettingsFile(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{"empty", `{}`, nil},
+		{"valid", `{"Version":2,"Language":"fr","Topics":["events"],"AuthServID":"mx","RequireAuth":{"subscribe-events":["dkim"]}}`, nil},
+		{"unknown field", `{"Titel":"Title"}`, []string{`unknown field "Titel"`}},
+		{"syntax", "{\n\"Title\":\n}", []string{"line 3: invalid character"}},
+		{"future version", `{"Version":99}`, []string{"Version: unsupported version 99"}},
+		{"future field", `{"Version":99,"Future":true}`, []string{"Version: unsupported version 99"}},
+		{"language", `{"Language":"de"}`, []string{`Language: unknown language "de"`}},
+		{"addresses", `{"Domain":"a@b","LocalPart":"a b","Delimiter":"++"}`, []string{
+			`Domain: invalid domain "a@b"`,
+			`LocalPart: invalid local part "a b"`,
+			`Delimiter: invalid delimiter "++"`,
+		}},
+		{"store", `{"Store":"sql"}`, []string{`Store: unknown store "sql"`}},
+		{"topics", `{"Topics":["a","Events","a"]}`, []string{`Topics[1]: invalid topic "Events"`, `Topics[2]: duplicate topic "a"`}},
+		{"expiry", `{"ConfirmExpiry":"-1h"}`, []string{"ConfirmExpiry: must not be negative"}},
+		{"blocklists", `{"BlockedDomains":["@"],"BlockedPatterns":["("]}`, []string{`BlockedDomains[0]: invalid domain "@"`, "BlockedPatterns[0]: error parsing regexp"}},
+		{"subscription policy", `{"SubscriptionPolicy":"closed"}`, []string{`SubscriptionPolicy: unknown policy "closed"`}},
+		{"editors", `{"Editors":["editor@club1.fr","<editor>"],"SendApprovals":4}`, []string{`Editors[1]: invalid address "<editor>"`, "SendApprovals: cannot be more than the 3 editors"}},
+		{"keywords", `{"SendKeyword":" GO","CancelKeyword":"STOP\nGO"}`, []string{`SendKeyword: invalid keyword " GO"`, `CancelKeyword: invalid keyword "STOP\nGO"`}},
+		{"same keywords", `{"SendKeyword":"go","CancelKeyword":"Go"}`, []string{"CancelKeyword: must be different from the send keyword"}},
+		{"time zone", `{"TimeZone":"Europe/Atlantis"}`, []string{`TimeZone: unknown time zone "Europe/Atlantis"`}},
+		{"auth", `{"RequireAuth":{"subscribe-events":["dkim"],"send":["arc"]}}`, []string{
+			"RequireAuth: requires AuthServID to be set",
+			`RequireAuth["send"][0]: unknown method "arc"`,
+			`RequireAuth: unknown route "subscribe-events"`,
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), newsletter.SettingsFile)
+			if err := os.WriteFile(path, []byte(c.content), 0660); err != nil {
+				t.Fatal(err)
+			}
+			err := newsletter.ValidateSettingsFile(path)
+			if c.expected == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected errors %q", c.expected)
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(c.expected) {
+				t.Fatalf("expected %d errors, got:\n%v", len(c.expected), err)
+			}
+			for i, expected := range c.expected {
+				if !strings.Contains(lines[i], expected) {
+					t.Errorf("expected error %d to contain %q, got: %q", i, expected, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestInitConfigInvalidSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := []byte(`{"Version":2,"Language":"de"}`)
+	if err := os.WriteFile(filepath.Join(tmpDir, newsletter.SettingsFile), content, 0660); err != nil {
+		t.Fatal(err)
+	}
+	_, err := newsletter.InitConfig(tmpDir)
+	if err == nil || !strings.Contains(err.Error(), `Language: unknown language "de"`) {
+		t.Errorf("expected invalid language error, got: %v", err)
+	}
+	saved, err := os.ReadFile(filepath.Join(tmpDir, newsletter.SettingsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(saved) != string(content) {
+		t.Errorf("expected invalid settings to be left untouched, got: %s", saved)
+	}
+}
@@ -0,0 +1,105 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// Available subscriber store backends, to be used in [Settings.Store].
+const (
+	StoreFile = "file"
+	StoreBolt = "bolt"
+)
+
+// Some error values.
+var (
+	ErrAlreadySubscribed = errors.New("already subscribed")
+)
+
+// Subscriber is an address subscribed to the newsletter, along with its
+// metadata and per-subscriber state.
+type Subscriber struct {
+	Address      string
+	Name         string            `json:",omitempty"`
+	Language     messages.Language `json:",omitempty"`
+	SubscribedAt time.Time         `json:",omitzero"`
+	// Topics are the topics the subscriber is interested in, an empty
+	// list meaning all of them.
+	Topics []string          `json:",omitempty"`
+	State  map[string]string `json:",omitempty"`
+}
+
+// SubscriberStore is the persistence backend of the subscribers of a
+// newsletter.
+//
+// Methods that take an address return [ErrNotSubscribed] if the address is
+// not part of the store, and [Add] returns [ErrAlreadySubscribed] if it is.
+type SubscriberStore interface {
+	// List returns all the subscribers, in a backend-defined order.
+	List() ([]Subscriber, error)
+	// Get returns the subscriber with the given address.
+	Get(addr string) (*Subscriber, error)
+	// Add adds a new subscriber to the store.
+	Add(sub *Subscriber) error
+	// Remove removes the subscriber with the given address.
+	Remove(addr string) error
+	// Update atomically modifies the subscriber with the given address.
+	// Changes are discarded if fn returns an error.
+	Update(addr string, fn func(sub *Subscriber) error) error
+	// All iterates over all the subscribers, stopping at the first error.
+	All() iter.Seq2[*Subscriber, error]
+	// Count returns the number of subscribers.
+	Count() (int, error)
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// OpenStore opens the subscriber store of the given backend kind, using
+// configDir to store its data. An empty kind selects [StoreFile].
+func OpenStore(kind string, configDir string) (SubscriberStore, error) {
+	switch kind {
+	case "", StoreFile:
+		return OpenFileStore(filepath.Join(configDir, EmailsFile))
+	case StoreBolt:
+		return OpenBoltStore(filepath.Join(configDir, BoltFile), filepath.Join(configDir, EmailsFile))
+	default:
+		return nil, fmt.Errorf("unknown store backend: %q", kind)
+	}
+}
+
+// Addresses returns the addresses of all the subscribers of the store.
+func Addresses(store SubscriberStore) ([]string, error) {
+	subs, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, len(subs))
+	for i, sub := range subs {
+		addrs[i] = sub.Address
+	}
+	return addrs, nil
+}
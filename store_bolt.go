@@ -0,0 +1,253 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var subscribersBucket = []byte("subscribers")
+
+// BoltStore is a [SubscriberStore] backed by an embedded bbolt key/value
+// database, where each subscriber is stored as a JSON value under its
+// address. Unlike [FileStore], changes only write the affected subscriber
+// and happen in a transaction.
+//
+// The database is only opened for the duration of each operation, as bbolt
+// holds an exclusive lock on the file while it is open, and several
+// processes may need to access it concurrently.
+type BoltStore struct {
+	path string
+}
+
+// OpenBoltStore opens the [BoltStore] at the given path, creating it if
+// needed. When the database is created, the subscribers of the [FileStore]
+// at importPath are imported into it, if any.
+func OpenBoltStore(path string, importPath string) (*BoltStore, error) {
+	s := &BoltStore{path: path}
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := s.importFileStore(importPath); err != nil {
+			return nil, fmt.Errorf("import subscribers: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("open subscribers database: %w", err)
+	}
+	return s, nil
+}
+
+func (s *BoltStore) importFileStore(path string) error {
+	file, err := OpenFileStore(path)
+	if err != nil {
+		return err
+	}
+	return s.update(func(b *bolt.Bucket) error {
+		for sub, err := range file.All() {
+			if err != nil {
+				return err
+			}
+			if err := putSubscriber(b, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BoltStore) open() (*bolt.DB, error) {
+	db, err := bolt.Open(s.path, 0660, &bolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open subscribers database: %w", err)
+	}
+	return db, nil
+}
+
+// view runs fn in a read-only transaction. The bucket is nil if no
+// subscriber has ever been added.
+func (s *BoltStore) view(fn func(b *bolt.Bucket) error) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(subscribersBucket))
+	})
+}
+
+// update runs fn in a read-write transaction.
+func (s *BoltStore) update(fn func(b *bolt.Bucket) error) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(subscribersBucket)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+func getSubscriber(b *bolt.Bucket, addr string) (*Subscriber, error) {
+	if b == nil {
+		return nil, ErrNotSubscribed
+	}
+	value := b.Get([]byte(addr))
+	if value == nil {
+		return nil, ErrNotSubscribed
+	}
+	var sub Subscriber
+	if err := json.Unmarshal(value, &sub); err != nil {
+		return nil, fmt.Errorf("decode subscriber %q: %w", addr, err)
+	}
+	return &sub, nil
+}
+
+func putSubscriber(b *bolt.Bucket, sub *Subscriber) error {
+	if sub.Address == "" {
+		return fmt.Errorf("invalid subscriber address: %q", sub.Address)
+	}
+	value, err := json.Marshal(sub)
+	if err != nil {
+		return fmt.Errorf("encode subscriber %q: %w", sub.Address, err)
+	}
+	return b.Put([]byte(sub.Address), value)
+}
+
+// List implements [SubscriberStore].
+func (s *BoltStore) List() ([]Subscriber, error) {
+	var subs []Subscriber
+	err := s.view(func(b *bolt.Bucket) error {
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var sub Subscriber
+			if err := json.Unmarshal(v, &sub); err != nil {
+				return fmt.Errorf("decode subscriber %q: %w", k, err)
+			}
+			subs = append(subs, sub)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// Get implements [SubscriberStore].
+func (s *BoltStore) Get(addr string) (*Subscriber, error) {
+	var sub *Subscriber
+	err := s.view(func(b *bolt.Bucket) error {
+		var err error
+		sub, err = getSubscriber(b, addr)
+		return err
+	})
+	return sub, err
+}
+
+// Add implements [SubscriberStore].
+func (s *BoltStore) Add(sub *Subscriber) error {
+	return s.update(func(b *bolt.Bucket) error {
+		if b.Get([]byte(sub.Address)) != nil {
+			return ErrAlreadySubscribed
+		}
+		return putSubscriber(b, sub)
+	})
+}
+
+// Remove implements [SubscriberStore].
+func (s *BoltStore) Remove(addr string) error {
+	return s.update(func(b *bolt.Bucket) error {
+		if b.Get([]byte(addr)) == nil {
+			return ErrNotSubscribed
+		}
+		return b.Delete([]byte(addr))
+	})
+}
+
+// Update implements [SubscriberStore].
+func (s *BoltStore) Update(addr string, fn func(sub *Subscriber) error) error {
+	return s.update(func(b *bolt.Bucket) error {
+		sub, err := getSubscriber(b, addr)
+		if err != nil {
+			return err
+		}
+		if err := fn(sub); err != nil {
+			return err
+		}
+		if sub.Address != addr {
+			if b.Get([]byte(sub.Address)) != nil {
+				return ErrAlreadySubscribed
+			}
+			if err := b.Delete([]byte(addr)); err != nil {
+				return err
+			}
+		}
+		return putSubscriber(b, sub)
+	})
+}
+
+// All implements [SubscriberStore].
+//
+// The subscribers are loaded before the iteration starts, so that the
+// database is not kept locked while the caller processes them.
+func (s *BoltStore) All() iter.Seq2[*Subscriber, error] {
+	return func(yield func(*Subscriber, error) bool) {
+		subs, err := s.List()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for i := range subs {
+			if !yield(&subs[i], nil) {
+				return
+			}
+		}
+	}
+}
+
+// Count implements [SubscriberStore].
+func (s *BoltStore) Count() (int, error) {
+	var count int
+	err := s.view(func(b *bolt.Bucket) error {
+		if b != nil {
+			count = b.Stats().KeyN
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Close implements [SubscriberStore].
+func (s *BoltStore) Close() error {
+	return nil
+}
@@ -0,0 +1,272 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// FileStore is a [SubscriberStore] that keeps the subscribers line by line in
+// a plain text file, entirely loaded in memory.
+//
+// Each line starts with the address of the subscriber, optionally followed by
+// a tab and its URL-encoded metadata, so that a list of bare addresses is a
+// valid file. New subscribers are appended to the file, other changes rewrite
+// it in full.
+type FileStore struct {
+	path string
+	subs []Subscriber
+}
+
+// OpenFileStore loads the [FileStore] at the given path. The file is not
+// created until the first subscriber is added.
+func OpenFileStore(path string) (*FileStore, error) {
+	lines, err := readLines(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read subscribers: %w", err)
+	}
+	subs := make([]Subscriber, 0, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		sub, err := parseSubscriberLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("parse subscriber at line %d: %w", i+1, err)
+		}
+		subs = append(subs, sub)
+	}
+	return &FileStore{path: path, subs: subs}, nil
+}
+
+func (s *FileStore) index(addr string) int {
+	return slices.IndexFunc(s.subs, func(sub Subscriber) bool {
+		return sub.Address == addr
+	})
+}
+
+// List implements [SubscriberStore].
+func (s *FileStore) List() ([]Subscriber, error) {
+	subs := make([]Subscriber, len(s.subs))
+	for i := range s.subs {
+		subs[i] = s.subs[i].clone()
+	}
+	return subs, nil
+}
+
+// Get implements [SubscriberStore].
+func (s *FileStore) Get(addr string) (*Subscriber, error) {
+	i := s.index(addr)
+	if i == -1 {
+		return nil, ErrNotSubscribed
+	}
+	sub := s.subs[i].clone()
+	return &sub, nil
+}
+
+// Add implements [SubscriberStore].
+func (s *FileStore) Add(sub *Subscriber) error {
+	if s.index(sub.Address) != -1 {
+		return ErrAlreadySubscribed
+	}
+	line, err := formatSubscriberLine(sub)
+	if err != nil {
+		return err
+	}
+	if err := appendLine(line, s.path); err != nil {
+		return fmt.Errorf("could not save subscriber: %w", err)
+	}
+	s.subs = append(s.subs, sub.clone())
+	return nil
+}
+
+// Remove implements [SubscriberStore].
+func (s *FileStore) Remove(addr string) error {
+	i := s.index(addr)
+	if i == -1 {
+		return ErrNotSubscribed
+	}
+	subs := slices.Delete(slices.Clone(s.subs), i, i+1)
+	return s.save(subs)
+}
+
+// Update implements [SubscriberStore].
+func (s *FileStore) Update(addr string, fn func(sub *Subscriber) error) error {
+	i := s.index(addr)
+	if i == -1 {
+		return ErrNotSubscribed
+	}
+	sub := s.subs[i].clone()
+	if err := fn(&sub); err != nil {
+		return err
+	}
+	if sub.Address != addr && s.index(sub.Address) != -1 {
+		return ErrAlreadySubscribed
+	}
+	subs := slices.Clone(s.subs)
+	subs[i] = sub
+	return s.save(subs)
+}
+
+// All implements [SubscriberStore].
+func (s *FileStore) All() iter.Seq2[*Subscriber, error] {
+	return func(yield func(*Subscriber, error) bool) {
+		for _, sub := range s.subs {
+			sub := sub.clone()
+			if !yield(&sub, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Count implements [SubscriberStore].
+func (s *FileStore) Count() (int, error) {
+	return len(s.subs), nil
+}
+
+// Close implements [SubscriberStore].
+func (s *FileStore) Close() error {
+	return nil
+}
+
+func (s *FileStore) save(subs []Subscriber) error {
+	lines := make([]string, len(subs))
+	for i := range subs {
+		line, err := formatSubscriberLine(&subs[i])
+		if err != nil {
+			return err
+		}
+		lines[i] = line
+	}
+	if err := writeLines(lines, s.path); err != nil {
+		return fmt.Errorf("could not save subscribers: %w", err)
+	}
+	s.subs = subs
+	return nil
+}
+
+func (sub *Subscriber) clone() Subscriber {
+	c := *sub
+	c.Topics = slices.Clone(sub.Topics)
+	c.State = maps.Clone(sub.State)
+	return c
+}
+
+const statePrefix = "state."
+
+func parseSubscriberLine(line string) (Subscriber, error) {
+	addr, meta, _ := strings.Cut(line, "\t")
+	sub := Subscriber{Address: strings.TrimSpace(addr)}
+	if meta == "" {
+		return sub, nil
+	}
+	values, err := url.ParseQuery(meta)
+	if err != nil {
+		return sub, fmt.Errorf("decode metadata: %w", err)
+	}
+	for key := range values {
+		value := values.Get(key)
+		switch {
+		case key == "name":
+			sub.Name = value
+		case key == "lang":
+			sub.Language = messages.Language(value)
+		case key == "since":
+			sub.SubscribedAt, err = time.Parse(time.RFC3339, value)
+			if err != nil {
+				return sub, fmt.Errorf("decode subscription date: %w", err)
+			}
+		case key == "topics":
+			sub.Topics = strings.Split(value, ",")
+		case strings.HasPrefix(key, statePrefix):
+			if sub.State == nil {
+				sub.State = make(map[string]string)
+			}
+			sub.State[strings.TrimPrefix(key, statePrefix)] = value
+		}
+	}
+	return sub, nil
+}
+
+func formatSubscriberLine(sub *Subscriber) (string, error) {
+	if sub.Address == "" || strings.ContainsAny(sub.Address, "\t\r\n") {
+		return "", fmt.Errorf("invalid subscriber address: %q", sub.Address)
+	}
+	values := url.Values{}
+	if sub.Name != "" {
+		values.Set("name", sub.Name)
+	}
+	if sub.Language != "" {
+		values.Set("lang", string(sub.Language))
+	}
+	if !sub.SubscribedAt.IsZero() {
+		values.Set("since", sub.SubscribedAt.Format(time.RFC3339))
+	}
+	if len(sub.Topics) > 0 {
+		values.Set("topics", strings.Join(sub.Topics, ","))
+	}
+	for key, value := range sub.State {
+		values.Set(statePrefix+key, value)
+	}
+	if len(values) == 0 {
+		return sub.Address, nil
+	}
+	return sub.Address + "\t" + values.Encode(), nil
+}
+
+// appendLine appends a line to the given file, creating it if needed and
+// making sure that the line starts at the beginning of a new line.
+func appendLine(line string, path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0664)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if size := stat.Size(); size > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, size-1); err != nil && err != io.EOF {
+			return err
+		}
+		if last[0] != '\n' {
+			line = "\n" + line
+		}
+	}
+	if _, err := file.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("write file error: %w", err)
+	}
+	return file.Close()
+}
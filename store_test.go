@@ -0,0 +1,181 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestStores(t *testing.T) {
+	cases := []struct {
+		name string
+		open func(dir string) (newsletter.SubscriberStore, error)
+	}{
+		{newsletter.StoreFile, func(dir string) (newsletter.SubscriberStore, error) {
+			return newsletter.OpenStore(newsletter.StoreFile, dir)
+		}},
+		{newsletter.StoreBolt, func(dir string) (newsletter.SubscriberStore, error) {
+			return newsletter.OpenStore(newsletter.StoreBolt, dir)
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			subTestStore(t, c.open)
+		})
+	}
+}
+
+func subTestStore(t *testing.T, open func(dir string) (newsletter.SubscriberStore, error)) {
+	dir := t.TempDir()
+	store, err := open(dir)
+	if err != nil {
+		t.Fatalf("open: unexpected error: %v", err)
+	}
+
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	subs := []*newsletter.Subscriber{
+		{Address: "a@club1.fr"},
+		{Address: "b@club1.fr", Name: "Bé Bé", Language: "fr", SubscribedAt: since},
+	}
+	for _, sub := range subs {
+		if err := store.Add(sub); err != nil {
+			t.Errorf("add: unexpected error: %v", err)
+		}
+	}
+	if err := store.Add(subs[0]); !errors.Is(err, newsletter.ErrAlreadySubscribed) {
+		t.Errorf("add twice: expected ErrAlreadySubscribed, got: %v", err)
+	}
+
+	err = store.Update("b@club1.fr", func(sub *newsletter.Subscriber) error {
+		sub.State = map[string]string{"bounces": "1"}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("update: unexpected error: %v", err)
+	}
+	err = store.Update("b@club1.fr", func(sub *newsletter.Subscriber) error {
+		sub.State["bounces"] = "2"
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Errorf("update: expected error")
+	}
+	if err := store.Update("c@club1.fr", nil); !errors.Is(err, newsletter.ErrNotSubscribed) {
+		t.Errorf("update missing: expected ErrNotSubscribed, got: %v", err)
+	}
+
+	// Reopen the store to check that everything has been persisted.
+	store.Close()
+	store, err = open(dir)
+	if err != nil {
+		t.Fatalf("reopen: unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	expected := &newsletter.Subscriber{
+		Address:      "b@club1.fr",
+		Name:         "Bé Bé",
+		Language:     "fr",
+		SubscribedAt: since,
+		State:        map[string]string{"bounces": "1"},
+	}
+	actual, err := store.Get("b@club1.fr")
+	if err != nil {
+		t.Errorf("get: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, actual)
+	}
+
+	if err := store.Remove("a@club1.fr"); err != nil {
+		t.Errorf("remove: unexpected error: %v", err)
+	}
+	if err := store.Remove("a@club1.fr"); !errors.Is(err, newsletter.ErrNotSubscribed) {
+		t.Errorf("remove twice: expected ErrNotSubscribed, got: %v", err)
+	}
+	if _, err := store.Get("a@club1.fr"); !errors.Is(err, newsletter.ErrNotSubscribed) {
+		t.Errorf("get removed: expected ErrNotSubscribed, got: %v", err)
+	}
+
+	count, err := store.Count()
+	if err != nil {
+		t.Errorf("count: unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 subscriber, got: %d", count)
+	}
+	for sub, err := range store.All() {
+		if err != nil {
+			t.Errorf("all: unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(sub, expected) {
+			t.Errorf("expected:\n%#v\ngot:\n%#v", expected, sub)
+		}
+	}
+}
+
+func TestFileStoreLegacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), newsletter.EmailsFile)
+	if err := os.WriteFile(path, []byte("a@club1.fr\nb@club1.fr"), 0664); err != nil {
+		t.Fatal(err)
+	}
+	store, err := newsletter.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open: unexpected error: %v", err)
+	}
+	if err := store.Add(&newsletter.Subscriber{Address: "c@club1.fr"}); err != nil {
+		t.Errorf("add: unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "a@club1.fr\nb@club1.fr\nc@club1.fr\n"
+	if string(content) != expected {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, content)
+	}
+}
+
+func TestBoltStoreImport(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, newsletter.EmailsFile), []byte("a@club1.fr\nb@club1.fr\n"), 0664)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := newsletter.OpenStore(newsletter.StoreBolt, dir)
+	if err != nil {
+		t.Fatalf("open: unexpected error: %v", err)
+	}
+	addrs, err := newsletter.Addresses(store)
+	if err != nil {
+		t.Errorf("list: unexpected error: %v", err)
+	}
+	expected := []string{"a@club1.fr", "b@club1.fr"}
+	if !reflect.DeepEqual(addrs, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, addrs)
+	}
+}
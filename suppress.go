@@ -0,0 +1,223 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Reasons why an address is on the suppression list.
+const (
+	SuppressUnsubscribed = "unsubscribed"
+	SuppressBounced      = "bounced"
+	SuppressRemoved      = "removed"
+)
+
+// Some error values.
+var (
+	ErrSuppressed = errors.New("address is suppressed")
+	ErrBlocked    = errors.New("address is blocked")
+)
+
+// Suppression is an entry of the suppression list.
+type Suppression struct {
+	// Key is the lowercased address, or a salted hash of it of the form
+	// `SALT:HASH` if [Settings.HashSuppressions] was set when it was added.
+	Key    string
+	Reason string
+	At     time.Time
+}
+
+// Matches reports whether the suppression entry matches the given address.
+func (s *Suppression) Matches(addr string) bool {
+	addr = strings.ToLower(addr)
+	salt, hash, hashed := strings.Cut(s.Key, ":")
+	if !hashed {
+		return s.Key == addr
+	}
+	return hash == hashString(salt+addr)
+}
+
+func parseSuppressionLine(line string) (Suppression, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 3 {
+		return Suppression{}, fmt.Errorf("expected 3 fields, got %d", len(fields))
+	}
+	at, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return Suppression{}, err
+	}
+	return Suppression{Key: fields[0], Reason: fields[1], At: at}, nil
+}
+
+func formatSuppressionLine(s *Suppression) string {
+	return s.Key + "\t" + s.Reason + "\t" + s.At.Format(time.RFC3339)
+}
+
+// Suppressions returns the entries of the suppression list.
+func (c *Config) Suppressions() ([]Suppression, error) {
+	lines, err := readLines(filepath.Join(c.Dir, SuppressionFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read suppression list: %w", err)
+	}
+	var suppressions []Suppression
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		s, err := parseSuppressionLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("parse suppression at line %d: %w", i+1, err)
+		}
+		suppressions = append(suppressions, s)
+	}
+	return suppressions, nil
+}
+
+// Suppression returns the suppression entry of the given address, or nil
+// if it is not suppressed.
+func (c *Config) Suppression(addr string) (*Suppression, error) {
+	suppressions, err := c.Suppressions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range suppressions {
+		if suppressions[i].Matches(addr) {
+			return &suppressions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Suppress adds the given address to the suppression list for the given
+// reason, replacing its previous entry if any.
+func (c *Config) Suppress(addr string, reason string) error {
+	if err := c.Unsuppress(addr); err != nil {
+		return err
+	}
+	key := strings.ToLower(addr)
+	if c.Settings.HashSuppressions {
+		salt := randString()[:8]
+		key = salt + ":" + hashString(salt+key)
+	}
+	s := Suppression{Key: key, Reason: reason, At: time.Now().UTC().Truncate(time.Second)}
+	if err := appendLine(formatSuppressionLine(&s), filepath.Join(c.Dir, SuppressionFile)); err != nil {
+		return fmt.Errorf("append suppression: %w", err)
+	}
+	return nil
+}
+
+// Unsuppress removes the given address from the suppression list.
+func (c *Config) Unsuppress(addr string) error {
+	suppressions, err := c.Suppressions()
+	if err != nil {
+		return err
+	}
+	var lines []string
+	found := false
+	for i := range suppressions {
+		if suppressions[i].Matches(addr) {
+			found = true
+			continue
+		}
+		lines = append(lines, formatSuppressionLine(&suppressions[i]))
+	}
+	if !found {
+		return nil
+	}
+	if len(lines) == 0 {
+		return os.Remove(filepath.Join(c.Dir, SuppressionFile))
+	}
+	return writeLines(lines, filepath.Join(c.Dir, SuppressionFile))
+}
+
+// IsBlocked reports whether the given address matches one of the blocked
+// domains or patterns of the settings.
+func (c *Config) IsBlocked(addr string) (bool, error) {
+	addr = strings.ToLower(addr)
+	_, domain, _ := strings.Cut(addr, "@")
+	for _, blocked := range c.Settings.BlockedDomains {
+		blocked = strings.ToLower(strings.TrimPrefix(blocked, "@"))
+		if domain == blocked || strings.HasSuffix(domain, "."+blocked) {
+			return true, nil
+		}
+	}
+	for _, pattern := range c.Settings.BlockedPatterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid blocked pattern %q: %w", pattern, err)
+		}
+		if re.MatchString(addr) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CheckAllowed returns [ErrBlocked] if the given address is blocked, or
+// an error wrapping [ErrSuppressed] and the reason if it is suppressed.
+// If optIn is set, the addresses that unsubscribed themselves are allowed,
+// as the request is an explicit opt-in of their owner, but not the removed
+// ones.
+func (c *Config) CheckAllowed(addr string, optIn bool) error {
+	blocked, err := c.IsBlocked(addr)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	s, err := c.Suppression(addr)
+	if err != nil {
+		return err
+	}
+	if s != nil && !(optIn && s.Reason == SuppressUnsubscribed) {
+		return fmt.Errorf("%w: %s", ErrSuppressed, s.Reason)
+	}
+	return nil
+}
+
+// Remove removes the given address from the subscribers, and suppresses it
+// so that it cannot be subscribed again by email, even by its owner. Only
+// [Config.Unsuppress] allows it again.
+func (c *Config) Remove(addr string) error {
+	if err := c.Subscribers.Remove(addr); err != nil {
+		return err
+	}
+	return c.Suppress(addr, SuppressRemoved)
+}
+
+// Bounce removes the given address from the subscribers if it is one, and
+// suppresses it as bounced so that news are not sent to it anymore.
+func (c *Config) Bounce(addr string) error {
+	if err := c.Subscribers.Remove(addr); err != nil && !errors.Is(err, ErrNotSubscribed) {
+		return err
+	}
+	return c.Suppress(addr, SuppressBounced)
+}
@@ -0,0 +1,147 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestSuppress(t *testing.T) {
+	for _, hashed := range []bool{false, true} {
+		nl := fakeNewsletter(t)
+		config := nl.Config
+		config.Settings.HashSuppressions = hashed
+
+		if err := config.Unsubscribe("recipient@club1.fr"); err != nil {
+			t.Fatalf("unsubscribe: %v", err)
+		}
+		if err := config.Suppress("bounce@club1.fr", newsletter.SuppressBounced); err != nil {
+			t.Fatalf("suppress: %v", err)
+		}
+		if err := config.Subscribe("removed@club1.fr"); err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		if err := config.Remove("removed@club1.fr"); err != nil {
+			t.Fatalf("remove: %v", err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(config.Dir, newsletter.SuppressionFile))
+		if err != nil {
+			t.Fatalf("read suppression list: %v", err)
+		}
+		if contains := strings.Contains(string(content), "recipient@club1.fr"); contains == hashed {
+			t.Errorf("hashed %v: unexpected suppression list content:\n%s", hashed, content)
+		}
+
+		cases := []struct {
+			addr     string
+			optIn    bool
+			expected error
+		}{
+			{"Recipient@Club1.fr", false, newsletter.ErrSuppressed},
+			{"recipient@club1.fr", true, nil},
+			{"bounce@club1.fr", true, newsletter.ErrSuppressed},
+			{"removed@club1.fr", true, newsletter.ErrSuppressed},
+			{"other@club1.fr", false, nil},
+		}
+		for _, c := range cases {
+			err := config.CheckAllowed(c.addr, c.optIn)
+			if !errors.Is(err, c.expected) {
+				t.Errorf("hashed %v: %s: expected error %v, got: %v", hashed, c.addr, c.expected, err)
+			}
+		}
+
+		if err := config.Unsuppress("bounce@club1.fr"); err != nil {
+			t.Fatalf("unsuppress: %v", err)
+		}
+		if err := config.CheckAllowed("bounce@club1.fr", false); err != nil {
+			t.Errorf("hashed %v: expected unsuppressed address to be allowed, got: %v", hashed, err)
+		}
+		suppressions, err := config.Suppressions()
+		if err != nil {
+			t.Fatalf("list suppressions: %v", err)
+		}
+		if len(suppressions) != 2 || suppressions[0].Reason != newsletter.SuppressUnsubscribed || suppressions[1].Reason != newsletter.SuppressRemoved {
+			t.Errorf("hashed %v: expected only the unsubscribed and removed addresses, got: %#v", hashed, suppressions)
+		}
+	}
+}
+
+func TestIsBlocked(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.BlockedDomains = []string{"spam.example", "@junk.example"}
+	nl.Config.Settings.BlockedPatterns = []string{`^noreply@`, `\+.*@club1\.fr$`}
+
+	cases := []struct {
+		addr     string
+		expected bool
+	}{
+		{"a@spam.example", true},
+		{"a@mx.spam.example", true},
+		{"a@notspam.example", false},
+		{"a@JUNK.example", true},
+		{"noreply@club1.fr", true},
+		{"user+tag@club1.fr", true},
+		{"user@club1.fr", false},
+	}
+	for _, c := range cases {
+		blocked, err := nl.Config.IsBlocked(c.addr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.addr, err)
+		}
+		if blocked != c.expected {
+			t.Errorf("%s: expected blocked %v, got %v", c.addr, c.expected, blocked)
+		}
+	}
+
+	nl.Config.Settings.BlockedPatterns = []string{"("}
+	if _, err := nl.Config.IsBlocked("user@club1.fr"); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
+
+func TestBounce(t *testing.T) {
+	nl := fakeNewsletter(t)
+	config := nl.Config
+	for _, addr := range []string{"recipient@club1.fr", "other@club1.fr"} {
+		if err := config.Bounce(addr); err != nil {
+			t.Fatalf("bounce %s: %v", addr, err)
+		}
+		if err := config.CheckAllowed(addr, true); !errors.Is(err, newsletter.ErrSuppressed) {
+			t.Errorf("%s: expected error %v, got: %v", addr, newsletter.ErrSuppressed, err)
+		}
+	}
+	if subscribed, err := config.IsSubscribed("recipient@club1.fr"); err != nil || subscribed {
+		t.Errorf("expected bounced address to be unsubscribed, got: %v (err: %v)", subscribed, err)
+	}
+	suppressions, err := config.Suppressions()
+	if err != nil {
+		t.Fatalf("list suppressions: %v", err)
+	}
+	if len(suppressions) != 2 || suppressions[0].Reason != newsletter.SuppressBounced {
+		t.Errorf("expected 2 bounced suppressions, got: %v", suppressions)
+	}
+}
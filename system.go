@@ -0,0 +1,141 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+// SystemConfigPath is the path of the optional system-wide config file.
+var SystemConfigPath = "/etc/newsletter/config.json"
+
+// ErrTooManySubscribers is returned when a subscriber is added to a list
+// that already has the maximum number of subscribers allowed by the policy.
+var ErrTooManySubscribers = errors.New("too many subscribers")
+
+// DefaultSendInterval is the delay between two mails when sending news.
+const DefaultSendInterval = 200 * time.Millisecond
+
+// SystemConfig is the system-wide config of the server, loaded from
+// [SystemConfigPath] before the settings of the lists.
+type SystemConfig struct {
+	Defaults SystemDefaults `json:",omitzero"`
+	Policy   Policy         `json:",omitzero"`
+}
+
+// SystemDefaults are the defaults of the lists of the server.
+type SystemDefaults struct {
+	// Hostname is the domain of the addresses of the lists, instead of
+	// the hostname of the server.
+	Hostname string `json:",omitempty"`
+	// Mailer is the mailer backend, "mailx" (the default) or "sendmail".
+	Mailer string `json:",omitempty"`
+	// Language is the language of the lists that do not set one.
+	Language messages.Language `json:",omitempty"`
+}
+
+// Policy is enforced by the server administrator on all the lists, and
+// cannot be overridden by their settings. Zero values mean no limit.
+type Policy struct {
+	// MaxSubscribers is the maximum number of subscribers of a list.
+	MaxSubscribers int `json:",omitzero"`
+	// MaxSendRate is the maximum number of mails sent per minute.
+	MaxSendRate int `json:",omitzero"`
+	// MaxAttachmentSize is the maximum total size of the attachments of
+	// a newsletter, in bytes.
+	MaxAttachmentSize int64 `json:",omitzero"`
+	// Footer is appended to all the news.
+	Footer string `json:",omitempty"`
+}
+
+// LoadSystemConfig loads the system-wide config file at the given path. A
+// missing file results in an empty config.
+func LoadSystemConfig(path string) (SystemConfig, error) {
+	var config SystemConfig
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := decodeStrict(data, &config); err != nil {
+		return config, err
+	}
+	return config, config.Validate()
+}
+
+// Validate checks the system config, and returns the errors of all its
+// invalid fields as [*SettingsError] joined together.
+func (s *SystemConfig) Validate() error {
+	var errs []error
+	if _, err := mailer.New(s.Defaults.Mailer); err != nil {
+		errs = append(errs, &SettingsError{"Defaults.Mailer", err.Error()})
+	}
+	switch s.Defaults.Language {
+	case "", messages.LangEnglish, messages.LangFrench:
+	default:
+		errs = append(errs, &SettingsError{"Defaults.Language", fmt.Sprintf("unknown language %q, must be %q or %q", s.Defaults.Language, messages.LangEnglish, messages.LangFrench)})
+	}
+	if s.Policy.MaxSubscribers < 0 {
+		errs = append(errs, &SettingsError{"Policy.MaxSubscribers", "must not be negative"})
+	}
+	if s.Policy.MaxSendRate < 0 {
+		errs = append(errs, &SettingsError{"Policy.MaxSendRate", "must not be negative"})
+	}
+	if s.Policy.MaxAttachmentSize < 0 {
+		errs = append(errs, &SettingsError{"Policy.MaxAttachmentSize", "must not be negative"})
+	}
+	return errors.Join(errs...)
+}
+
+// SendInterval returns the delay between two mails when sending news,
+// [DefaultSendInterval] unless the policy requires a lower rate.
+func (nl *Newsletter) SendInterval() time.Duration {
+	if rate := nl.Config.System.Policy.MaxSendRate; rate > 0 {
+		return max(DefaultSendInterval, time.Minute/time.Duration(rate))
+	}
+	return DefaultSendInterval
+}
+
+// limitedStore is a [SubscriberStore] that refuses to add subscribers
+// beyond a maximum.
+type limitedStore struct {
+	SubscriberStore
+	max int
+}
+
+// Add implements [SubscriberStore].
+func (s *limitedStore) Add(sub *Subscriber) error {
+	count, err := s.Count()
+	if err != nil {
+		return err
+	}
+	if count >= s.max {
+		return fmt.Errorf("%w: the maximum is %d", ErrTooManySubscribers, s.max)
+	}
+	return s.SubscriberStore.Add(sub)
+}
@@ -0,0 +1,116 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+func TestMain(m *testing.M) {
+	// do not depend on the config of the machine running the tests
+	newsletter.SystemConfigPath = filepath.Join("testdata", "missing", "config.json")
+	os.Unsetenv(newsletter.ConfigDirEnv)
+	os.Unsetenv("XDG_CONFIG_HOME")
+	os.Exit(m.Run())
+}
+
+func writeSystemConfig(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	previous := newsletter.SystemConfigPath
+	newsletter.SystemConfigPath = path
+	t.Cleanup(func() { newsletter.SystemConfigPath = previous })
+}
+
+func TestLoadSystemConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"valid", `{"Defaults":{"Mailer":"sendmail","Language":"fr"},"Policy":{"MaxSubscribers":10}}`, ""},
+		{"unknown field", `{"Policy":{"MaxSubscriber":10}}`, `unknown field "MaxSubscriber"`},
+		{"invalid", `{"Defaults":{"Mailer":"pigeon"},"Policy":{"MaxSendRate":-1}}`, "Defaults.Mailer: unknown mailer backend \"pigeon\"\nPolicy.MaxSendRate: must not be negative"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			writeSystemConfig(t, c.content)
+			_, err := newsletter.LoadSystemConfig(newsletter.SystemConfigPath)
+			if c.expected == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if c.expected != "" && (err == nil || !strings.Contains(err.Error(), c.expected)) {
+				t.Errorf("expected error containing %q, got: %v", c.expected, err)
+			}
+		})
+	}
+}
+
+func TestInitConfigSystem(t *testing.T) {
+	writeSystemConfig(t, `{
+		"Defaults": {"Language": "fr"},
+		"Policy": {"MaxSubscribers": 1, "MaxSendRate": 60, "Footer": "Hosted by CLUB1"}
+	}`)
+	config, err := newsletter.InitConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("init config: %v", err)
+	}
+	defer config.Close()
+	if lang := config.LanguageOrDefault(); lang != messages.LangFrench {
+		t.Errorf("expected default language %q, got %q", messages.LangFrench, lang)
+	}
+	if err := config.SaveSettings(); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+	saved, err := newsletter.InitConfig(config.Dir)
+	if err != nil {
+		t.Fatalf("init config: %v", err)
+	}
+	defer saved.Close()
+	if saved.Settings.Language != "" {
+		t.Errorf("expected the default language not to be saved, got %q", saved.Settings.Language)
+	}
+
+	if err := config.Subscribe("a@club1.fr"); err != nil {
+		t.Errorf("subscribe: unexpected error: %v", err)
+	}
+	if err := config.Subscribe("b@club1.fr"); !errors.Is(err, newsletter.ErrTooManySubscribers) {
+		t.Errorf("expected too many subscribers error, got: %v", err)
+	}
+
+	nl := &newsletter.Newsletter{Config: config, Hostname: "club1.fr", LocalUser: "user"}
+	if interval := nl.SendInterval(); interval != time.Second {
+		t.Errorf("expected send interval of 1s, got %v", interval)
+	}
+	if footer := nl.Footer(""); !strings.HasSuffix(footer, "\n\nHosted by CLUB1") {
+		t.Errorf("expected footer to end with the policy footer, got: %q", footer)
+	}
+}
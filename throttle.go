@@ -0,0 +1,183 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Default limits of the confirmation mails sent in reply to subscription
+// requests.
+const (
+	DefaultThrottleCooldown  = time.Hour
+	DefaultThrottleWindow    = time.Hour
+	DefaultThrottlePerDomain = 20
+	DefaultThrottleGlobal    = 100
+)
+
+// ErrThrottled is returned when a confirmation mail would exceed the limits.
+var ErrThrottled = errors.New("too many confirmation mails")
+
+// ThrottleSettings are the limits of the confirmation mails sent in reply
+// to subscription requests, to prevent the newsletter from being used to
+// flood arbitrary addresses. Zero values mean the default, and negative
+// values disable the limit.
+type ThrottleSettings struct {
+	// Cooldown is the minimum delay between two mails to the same address.
+	Cooldown Duration `json:",omitzero"`
+	// Window is the period during which PerDomain and Global are counted.
+	Window Duration `json:",omitzero"`
+	// PerDomain is the maximum number of mails to a domain per Window.
+	PerDomain int `json:",omitzero"`
+	// Global is the maximum number of mails per Window.
+	Global int `json:",omitzero"`
+}
+
+func orDefault[T Duration | int](value T, def T) T {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+type throttleEntry struct {
+	at   time.Time
+	addr string
+}
+
+func readThrottleEntries(path string) ([]throttleEntry, error) {
+	lines, err := readLines(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []throttleEntry
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		date, addr, _ := strings.Cut(line, "\t")
+		at, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		entries = append(entries, throttleEntry{at: at, addr: addr})
+	}
+	return entries, nil
+}
+
+// lockFile takes an exclusive lock on the file at the given path, creating
+// it if needed, waiting for the other processes that hold it. The lock is
+// released when the returned file is closed.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0660)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func addrDomain(addr string) string {
+	_, domain, _ := strings.Cut(strings.ToLower(addr), "@")
+	return domain
+}
+
+// Throttle records a confirmation mail to the given address, or returns an
+// error wrapping [ErrThrottled] if it would exceed the limits of
+// [Settings.Throttle].
+func (nl *Newsletter) Throttle(addr string) error {
+	return nl.throttle(filepath.Join(nl.Config.Dir, ThrottleFile), addr)
+}
+
+// ThrottleNotice is like [Newsletter.Throttle] for the notices sent to the
+// owner about the rejected requests of the given address. They are counted
+// apart, so that forged requests cannot use up the confirmation mails.
+func (nl *Newsletter) ThrottleNotice(addr string) error {
+	return nl.throttle(filepath.Join(nl.Config.Dir, NoticeThrottleFile), addr)
+}
+
+func (nl *Newsletter) throttle(path string, addr string) error {
+	settings := nl.Config.Settings.Throttle
+	cooldown := time.Duration(orDefault(settings.Cooldown, Duration(DefaultThrottleCooldown)))
+	window := time.Duration(orDefault(settings.Window, Duration(DefaultThrottleWindow)))
+	perDomain := orDefault(settings.PerDomain, DefaultThrottlePerDomain)
+	global := orDefault(settings.Global, DefaultThrottleGlobal)
+
+	// the entries are read and written back by concurrent deliveries, the
+	// lock is on a separate file as the entries file is replaced
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock throttle entries: %w", err)
+	}
+	defer lock.Close()
+	entries, err := readThrottleEntries(path)
+	if err != nil {
+		return fmt.Errorf("read throttle entries: %w", err)
+	}
+
+	now := nl.now().UTC().Truncate(time.Second)
+	addr = strings.ToLower(addr)
+	var kept []string
+	addrCount, domainCount, globalCount := 0, 0, 0
+	for _, e := range entries {
+		age := now.Sub(e.at)
+		if age >= cooldown && age >= window {
+			continue
+		}
+		kept = append(kept, e.at.Format(time.RFC3339)+"\t"+e.addr)
+		if age < cooldown && e.addr == addr {
+			addrCount++
+		}
+		if age < window {
+			globalCount++
+			if addrDomain(e.addr) == addrDomain(addr) {
+				domainCount++
+			}
+		}
+	}
+
+	switch {
+	case cooldown > 0 && addrCount > 0:
+		return fmt.Errorf("%w: address in cooldown for %v", ErrThrottled, cooldown)
+	case perDomain > 0 && domainCount >= perDomain:
+		return fmt.Errorf("%w: %d for domain %q in %v", ErrThrottled, domainCount, addrDomain(addr), window)
+	case global > 0 && globalCount >= global:
+		return fmt.Errorf("%w: %d in %v", ErrThrottled, globalCount, window)
+	}
+
+	kept = append(kept, now.Format(time.RFC3339)+"\t"+addr)
+	content := strings.Join(kept, "\n") + "\n"
+	if err := writeFileAtomic(path, []byte(content), 0660); err != nil {
+		return fmt.Errorf("write throttle entries: %w", err)
+	}
+	return nil
+}
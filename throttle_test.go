@@ -0,0 +1,104 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestThrottle(t *testing.T) {
+	nl := fakeNewsletter(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	nl.Config.Settings.Throttle = newsletter.ThrottleSettings{
+		Cooldown:  newsletter.Duration(10 * time.Minute),
+		Window:    newsletter.Duration(time.Hour),
+		PerDomain: 2,
+		Global:    3,
+	}
+
+	cases := []struct {
+		addr     string
+		after    time.Duration
+		expected error
+	}{
+		{"a@club1.fr", 0, nil},
+		{"A@club1.fr", time.Minute, newsletter.ErrThrottled},
+		{"a@club1.fr", 10 * time.Minute, nil},
+		{"b@club1.fr", 11 * time.Minute, newsletter.ErrThrottled},
+		{"b@example.org", 12 * time.Minute, nil},
+		{"c@example.org", 13 * time.Minute, newsletter.ErrThrottled},
+		{"c@example.org", time.Hour + time.Minute, nil},
+	}
+	for _, c := range cases {
+		nl.Now = func() time.Time { return now.Add(c.after) }
+		err := nl.Throttle(c.addr)
+		if !errors.Is(err, c.expected) {
+			t.Errorf("%s after %v: expected error %v, got: %v", c.addr, c.after, c.expected, err)
+		}
+	}
+
+	nl.Config.Settings.Throttle = newsletter.ThrottleSettings{Cooldown: -1, PerDomain: -1, Global: -1}
+	for range 5 {
+		if err := nl.Throttle("a@club1.fr"); err != nil {
+			t.Errorf("expected disabled limits, got: %v", err)
+		}
+	}
+}
+
+func TestThrottleNotice(t *testing.T) {
+	nl := fakeNewsletter(t)
+	if err := nl.ThrottleNotice("a@club1.fr"); err != nil {
+		t.Fatalf("first notice: unexpected error: %v", err)
+	}
+	if err := nl.ThrottleNotice("a@club1.fr"); !errors.Is(err, newsletter.ErrThrottled) {
+		t.Errorf("second notice: expected error %v, got: %v", newsletter.ErrThrottled, err)
+	}
+	if err := nl.Throttle("a@club1.fr"); err != nil {
+		t.Errorf("expected confirmation not to be throttled by the notice, got: %v", err)
+	}
+}
+
+func TestThrottleConcurrent(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.Throttle = newsletter.ThrottleSettings{Global: 10}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	nl.Now = func() time.Time { return now }
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := nl.Throttle(fmt.Sprintf("user@domain%d.fr", i)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := nl.Throttle("user@club1.fr"); !errors.Is(err, newsletter.ErrThrottled) {
+		t.Errorf("expected all the concurrent mails to be recorded, got: %v", err)
+	}
+}
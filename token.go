@@ -0,0 +1,209 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Purposes of the tokens, so that a token issued for one action cannot be
+// used for another one.
+const (
+	PurposeSubscribe = "sub"
+	PurposeSend      = "send"
+)
+
+const (
+	tokenVersion = "2"
+
+	// DefaultConfirmExpiry is the validity of the tokens when
+	// [Settings.ConfirmExpiry] is not set.
+	DefaultConfirmExpiry = 7 * 24 * time.Hour
+)
+
+// Some error values.
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("expired token")
+)
+
+var tokenEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (nl *Newsletter) now() time.Time {
+	if nl.Now != nil {
+		return nl.Now()
+	}
+	return time.Now()
+}
+
+func tokenMAC(secret string, purpose string, issued string, subject string, data []string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	// Fields are separated by NUL bytes, which cannot appear in them.
+	fields := append([]string{tokenVersion, purpose, issued, subject}, data...)
+	mac.Write([]byte(strings.Join(fields, "\x00")))
+	return tokenEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewToken creates a token for the given purpose, bound to the given
+// subject (usually an address) and carrying the given data, that can be
+// checked with [Newsletter.VerifyToken].
+//
+// Tokens are of the form `VERSION.PURPOSE.ISSUED.MAC[.DATA]...`, where MAC
+// is a HMAC-SHA256 of the other fields and the subject. The data must not
+// contain any dot, so that the token can be used in a Message-ID.
+func (nl *Newsletter) NewToken(purpose string, subject string, data ...string) string {
+	issued := strconv.FormatInt(nl.now().Unix(), 36)
+	mac := tokenMAC(nl.Config.Secret, purpose, issued, subject, data)
+	return strings.Join(append([]string{tokenVersion, purpose, issued, mac}, data...), ".")
+}
+
+// VerifyToken checks that the token has been issued by [Newsletter.NewToken]
+// for the given purpose and subject, and that it has not expired. It returns
+// the data carried by the token.
+func (nl *Newsletter) VerifyToken(token string, purpose string, subject string) ([]string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) < 4 || parts[0] != tokenVersion || parts[1] != purpose {
+		return nil, ErrInvalidToken
+	}
+	issued, mac, data := parts[2], parts[3], parts[4:]
+	valid := false
+	for _, secret := range nl.Config.Secrets(nl.now()) {
+		expected := tokenMAC(secret, purpose, issued, subject, data)
+		if hmac.Equal([]byte(mac), []byte(expected)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidToken
+	}
+	seconds, err := strconv.ParseInt(issued, 36, 64)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if nl.now().Sub(time.Unix(seconds, 0)) > nl.Config.Settings.ConfirmExpiryOrDefault() {
+		return nil, ErrExpiredToken
+	}
+	return data, nil
+}
+
+// IsLegacyToken reports whether the token has been generated by a previous
+// version, as a hash without issue time.
+func IsLegacyToken(token string) bool {
+	return !strings.HasPrefix(token, tokenVersion+".")
+}
+
+// acceptLegacyTokens reports whether legacy tokens are still in their
+// compatibility window.
+func (nl *Newsletter) acceptLegacyTokens() bool {
+	return nl.now().Before(nl.Config.Settings.LegacyTokensUntil)
+}
+
+// legacyConfirmID returns the subscription confirmation Message-ID used by
+// previous versions, generated using the given secret.
+func (nl *Newsletter) legacyConfirmID(secret string, addr string, topics []string) string {
+	if len(topics) == 0 {
+		return nl.GenerateId(hashString(addr + secret))
+	}
+	hash := hashString(addr + "\n" + strings.Join(topics, ",") + secret)
+	return nl.GenerateId(hash + "." + strings.Join(topics, "."))
+}
+
+// ConfirmID returns the Message-ID of the subscription confirmation mail
+// sent to the given address for the given topics.
+func (nl *Newsletter) ConfirmID(addr string, topics []string) string {
+	return nl.GenerateId(nl.NewToken(PurposeSubscribe, addr, topics...))
+}
+
+// VerifyConfirmID checks that messageID has been generated by
+// [Newsletter.ConfirmID] for the given address, and returns the topics it
+// carries. Legacy IDs are accepted during their compatibility window.
+func (nl *Newsletter) VerifyConfirmID(messageID string, addr string) ([]string, error) {
+	token, err := nl.GetHashFromId(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var topics []string
+	if IsLegacyToken(token) {
+		if !nl.acceptLegacyTokens() {
+			return nil, ErrExpiredToken
+		}
+		topics = strings.Split(token, ".")[1:]
+		valid := false
+		for _, secret := range nl.Config.Secrets(nl.now()) {
+			if messageID == nl.legacyConfirmID(secret, addr, topics) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, ErrInvalidToken
+		}
+	} else {
+		topics, err = nl.VerifyToken(token, PurposeSubscribe, addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, topic := range topics {
+		if !slices.Contains(nl.Config.Settings.Topics, topic) {
+			return nil, ErrInvalidToken
+		}
+	}
+	return topics, nil
+}
+
+// SendID returns the Message-ID of the preview mail of the issue with the
+// given hash, whose confirmation is expected from the given address.
+func (nl *Newsletter) SendID(addr string, hash string) string {
+	return nl.GenerateId(nl.NewToken(PurposeSend, addr, hash))
+}
+
+// VerifySendID checks that messageID has been generated by
+// [Newsletter.SendID] for the given address, and returns the hash of the
+// issue. Legacy IDs are always expired: they are the bare hash of the
+// issue, that is not bound to an editor and can be read in any preview ID,
+// and the issues they referred to are not stored anymore.
+func (nl *Newsletter) VerifySendID(messageID string, addr string) (string, error) {
+	token, err := nl.GetHashFromId(messageID)
+	if err != nil {
+		return "", err
+	}
+	if IsLegacyToken(token) {
+		return "", ErrExpiredToken
+	}
+	data, err := nl.VerifyToken(token, PurposeSend, addr)
+	if err != nil {
+		return "", err
+	}
+	if len(data) != 1 {
+		return "", ErrInvalidToken
+	}
+	return data[0], nil
+}
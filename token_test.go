@@ -0,0 +1,77 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/club-1/newsletter-go/v3"
+)
+
+func TestToken(t *testing.T) {
+	nl := fakeNewsletter(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	nl.Now = func() time.Time { return now }
+	token := nl.NewToken(newsletter.PurposeSubscribe, "a@club1.fr", "events")
+
+	cases := []struct {
+		name     string
+		purpose  string
+		subject  string
+		after    time.Duration
+		expected error
+	}{
+		{"valid", newsletter.PurposeSubscribe, "a@club1.fr", newsletter.DefaultConfirmExpiry, nil},
+		{"expired", newsletter.PurposeSubscribe, "a@club1.fr", newsletter.DefaultConfirmExpiry + time.Second, newsletter.ErrExpiredToken},
+		{"wrong purpose", newsletter.PurposeSend, "a@club1.fr", 0, newsletter.ErrInvalidToken},
+		{"wrong subject", newsletter.PurposeSubscribe, "b@club1.fr", 0, newsletter.ErrInvalidToken},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			nl.Now = func() time.Time { return now.Add(c.after) }
+			data, err := nl.VerifyToken(token, c.purpose, c.subject)
+			if !errors.Is(err, c.expected) {
+				t.Errorf("expected error %v, got: %v", c.expected, err)
+			}
+			if err == nil && !reflect.DeepEqual(data, []string{"events"}) {
+				t.Errorf("expected data %q, got: %q", []string{"events"}, data)
+			}
+		})
+	}
+}
+
+func TestVerifyConfirmIDLegacy(t *testing.T) {
+	nl := fakeNewsletter(t)
+	legacyID := "user-NRGABAKKE6AKVXM5S7IJQOUFFOXC2B3UF5QWX5VYFAKBRNWHZBHQ====@club1.fr"
+	nl.Config.Settings.LegacyTokensUntil = time.Now().Add(time.Hour)
+	if _, err := nl.VerifyConfirmID(legacyID, "test@club1.fr"); err != nil {
+		t.Errorf("expected legacy ID to be accepted, got: %v", err)
+	}
+	if _, err := nl.VerifyConfirmID(legacyID, "other@club1.fr"); !errors.Is(err, newsletter.ErrInvalidToken) {
+		t.Errorf("expected legacy ID of another address to be rejected, got: %v", err)
+	}
+	nl.Config.Settings.LegacyTokensUntil = time.Now().Add(-time.Hour)
+	if _, err := nl.VerifyConfirmID(legacyID, "test@club1.fr"); !errors.Is(err, newsletter.ErrExpiredToken) {
+		t.Errorf("expected legacy ID to be expired, got: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/messages"
+)
+
+var topicRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateTopic checks that the given name can be used as a topic.
+func ValidateTopic(name string) error {
+	if !topicRegexp.MatchString(name) || name == "confirm" {
+		return fmt.Errorf("invalid topic %q: must only contain lowercase letters, digits, '-' and '_', and not be \"confirm\"", name)
+	}
+	return nil
+}
+
+// SubscribeTopicRoute returns the route used to subscribe to the given topic.
+func SubscribeTopicRoute(topic string) string {
+	return RouteSubscribe + "-" + topic
+}
+
+// ForwardRoutes returns all the routes of the newsletter, including the
+// subscription routes of its topics.
+func (nl *Newsletter) ForwardRoutes() []string {
+	routes := slices.Clone(Routes[:])
+	if route := nl.SendTokenRoute(); route != "" {
+		routes = append(routes, route)
+	}
+	for _, topic := range nl.Config.Settings.Topics {
+		routes = append(routes, SubscribeTopicRoute(topic))
+	}
+	return routes
+}
+
+// HasTopic reports whether the subscriber receives the news of the given
+// topic. Subscribers without topics receive all of them, and all the
+// subscribers receive the news sent without topic.
+func (sub *Subscriber) HasTopic(topic string) bool {
+	return topic == "" || len(sub.Topics) == 0 || slices.Contains(sub.Topics, topic)
+}
+
+// FindTopics returns the topics of the newsletter that appear as words in
+// the given texts.
+func (nl *Newsletter) FindTopics(texts ...string) []string {
+	var topics []string
+	for _, text := range texts {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+		})
+		for _, word := range words {
+			if slices.Contains(nl.Config.Settings.Topics, word) && !slices.Contains(topics, word) {
+				topics = append(topics, word)
+			}
+		}
+	}
+	return topics
+}
+
+// SubscribeTopics subscribes the given address to the given topics, or to
+// all of them if topics is empty. If the address is already subscribed,
+// the topics are added to its existing ones.
+func (c *Config) SubscribeTopics(addr string, topics []string) error {
+	err := c.Subscribers.Update(addr, func(sub *Subscriber) error {
+		if len(sub.Topics) == 0 {
+			return nil
+		}
+		if len(topics) == 0 {
+			sub.Topics = nil
+			return nil
+		}
+		for _, topic := range topics {
+			if !slices.Contains(sub.Topics, topic) {
+				sub.Topics = append(sub.Topics, topic)
+			}
+		}
+		return nil
+	})
+	if !errors.Is(err, ErrNotSubscribed) {
+		return err
+	}
+	return c.Subscribers.Add(&Subscriber{
+		Address:      addr,
+		SubscribedAt: time.Now().UTC().Truncate(time.Second),
+		Topics:       topics,
+	})
+}
+
+// TopicsLine returns a line naming the given topics, to be appended to a
+// mail body, or an empty string if there is no topic.
+func TopicsLine(topics []string) string {
+	if len(topics) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(messages.Topics_line.Print(), strings.Join(topics, ", "))
+}
+
+// Footer returns the footer to append to the news of the given topic, or
+// to all the news if topic is empty.
+func (nl *Newsletter) Footer(topic string) string {
+	footer := fmt.Sprintf(messages.Newsletter_footer.Print(), nl.UnsubscribeAddr())
+	if topic != "" {
+		footer += fmt.Sprintf(messages.NewsletterTopic_footer.Print(), topic)
+	}
+	if policy := nl.Config.System.Policy.Footer; policy != "" {
+		footer += "\n\n" + policy
+	}
+	return footer
+}
+
+// Segment iterates over the subscribers of the given topic, or all the
+// subscribers if topic is empty.
+func (nl *Newsletter) Segment(topic string) iter.Seq2[*Subscriber, error] {
+	return func(yield func(*Subscriber, error) bool) {
+		for sub, err := range nl.Config.Subscribers.All() {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if sub.HasTopic(topic) && !yield(sub, nil) {
+				return
+			}
+		}
+	}
+}
+
+// CountSegment returns the number of subscribers of the given topic, or of
+// all the subscribers if topic is empty.
+func (nl *Newsletter) CountSegment(topic string) (int, error) {
+	if topic == "" {
+		return nl.Config.Subscribers.Count()
+	}
+	count := 0
+	for _, err := range nl.Segment(topic) {
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// SendSegment sends the given mail to all the addresses subscribed to the
+// given topic. An empty topic sends it to all the subscribers.
+func (nl *Newsletter) SendSegment(mail *mailer.Mail, topic string) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		for sub, err := range nl.Segment(topic) {
+			if err != nil {
+				yield(fmt.Errorf("list subscribers: %w", err))
+				return
+			}
+			time.Sleep(nl.SendInterval())
+			mail.To = sub.Address
+			if !yield(nl.Mailer.Send(mail)) {
+				return
+			}
+		}
+	}
+}
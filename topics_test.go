@@ -0,0 +1,100 @@
+// This file is part of club-1/newsletter-go.
+//
+// Copyright (c) 2026 CLUB1 Members <contact@club1.fr>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+
+package newsletter_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/club-1/newsletter-go/v3"
+	"github.com/club-1/newsletter-go/v3/mailer"
+	"github.com/club-1/newsletter-go/v3/mailer/mailertest"
+)
+
+func TestFindTopics(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.Topics = []string{"events", "project-digest"}
+
+	actual := nl.FindTopics("Subscribe: Project-Digest, events", "events again, eventsx")
+	expected := []string{"project-digest", "events"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%#v\ngot:\n%#v", expected, actual)
+	}
+}
+
+func TestSendSegment(t *testing.T) {
+	nl := fakeNewsletter(t)
+	nl.Config.Settings.Topics = []string{"events", "digest"}
+	subs := []*newsletter.Subscriber{
+		{Address: "events@club1.fr", Topics: []string{"events"}},
+		{Address: "digest@club1.fr", Topics: []string{"digest"}},
+	}
+	for _, sub := range subs {
+		if err := nl.Config.Subscribers.Add(sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var actual []string
+	nl.Mailer = &mailertest.Mailer{Handler: func(mail *mailer.Mail) error {
+		actual = append(actual, mail.To)
+		return nil
+	}}
+	for err := range nl.SendSegment(&mailer.Mail{}, "events") {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{"recipient@club1.fr", "events@club1.fr"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected recipients:\n%#v\ngot:\n%#v", expected, actual)
+	}
+	count, err := nl.CountSegment("digest")
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 digest subscribers, got: %d (err: %v)", count, err)
+	}
+}
+
+func TestSubscribeTopics(t *testing.T) {
+	nl := fakeNewsletter(t)
+	config := nl.Config
+	steps := []struct {
+		topics   []string
+		expected []string
+	}{
+		{[]string{"events"}, []string{"events"}},
+		{[]string{"digest", "events"}, []string{"events", "digest"}},
+		{nil, nil},
+		{[]string{"events"}, nil},
+	}
+	for _, step := range steps {
+		if err := config.SubscribeTopics("new@club1.fr", step.topics); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		sub, err := config.Subscribers.Get("new@club1.fr")
+		if err != nil {
+			t.Fatalf("get: unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(sub.Topics, step.expected) {
+			t.Errorf("after subscribing to %q, expected topics %q, got: %q", step.topics, step.expected, sub.Topics)
+		}
+	}
+}